@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/questions/backend/internal/config"
+)
+
+const configUsage = "usage: questions_backend config print"
+
+// runConfig implements the "config" subcommand. "print" writes the effective
+// configuration as YAML with secrets redacted, then fails if any setting is
+// invalid, listing them all.
+func runConfig(cfg *config.Config, cfgErr error, args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		log.Fatal(configUsage)
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		log.Fatalf("Failed to render configuration: %v", err)
+	}
+	os.Stdout.Write(out)
+
+	if cfgErr != nil {
+		log.Fatal(cfgErr)
+	}
+}
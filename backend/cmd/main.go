@@ -1,45 +1,258 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
-	"github.com/joho/godotenv"
+	"github.com/questions/backend/internal/api"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/config"
+	"github.com/questions/backend/internal/counters"
 	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/health"
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/metrics"
+	"github.com/questions/backend/internal/ratelimit"
 	"github.com/questions/backend/internal/router"
+	"github.com/questions/backend/internal/store"
+	"github.com/questions/backend/internal/tracing"
+	"github.com/redis/go-redis/v9"
 )
 
+// version and commit identify the build in /status; set them with
+// -ldflags "-X main.version=... -X main.commit=...". The commit defaults to
+// the one go build stamps from the git checkout.
+var version, commit string
+
 func main() {
-	// Load environment variables from .env file
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: Error loading .env file:", err)
+	// Load the configuration from CONFIG_FILE, .env and the environment
+	cfg, cfgErr := config.Load()
+
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfig(cfg, cfgErr, os.Args[2:])
+		return
+	}
+	if cfgErr != nil {
+		log.Fatal(cfgErr)
+	}
+
+	// Log in the configured format and level; the log package and the
+	// subcommands log through the same logger
+	logger, err := logging.New(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(cfg, os.Args[2:])
+			return
+		case "search":
+			runSearch(cfg, logger, os.Args[2:])
+			return
+		case "tags":
+			runTags(cfg, os.Args[2:])
+			return
+		case "users":
+			runUsers(cfg, os.Args[2:])
+			return
+		default:
+			log.Fatalf("Unknown command %q", os.Args[1])
+		}
+	}
+
+	tokens := newTokenManager(logger, cfg.JWT)
+
+	// Collect Prometheus metrics unless disabled; nil metrics record nothing
+	var m *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		m = metrics.New()
+	}
+
+	// Trace requests, SQL queries and Redis commands unless
+	// TRACING_EXPORTER=none; set up first so the connections are traced
+	stopTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal(logger, "setting up tracing failed", err)
 	}
 
-	// Initialize MySQL database connection
-	if err := db.InitMySQL(); err != nil {
-		log.Fatalf("Failed to initialize MySQL: %v", err)
+	// Report liveness, readiness and the build for probes; MySQL is required
+	// to serve, while Redis has an in-process fallback
+	checker := health.New(health.NewBuild(version, commit), cfg.Health.CheckTimeout)
+
+	var st store.Store
+	var rdb *redis.Client
+
+	// STORAGE=memory runs the API without MySQL, and without Redis unless
+	// CACHE_BACKEND=redis, e.g. for local demos
+	if cfg.Storage.Backend == "memory" {
+		logger.Warn("using in-memory storage; data will not be persisted")
+		st = store.NewMemoryStore()
+	} else {
+		// Initialize MySQL database connection
+		if err := db.InitMySQL(cfg.MySQL); err != nil {
+			fatal(logger, "initializing MySQL failed", err)
+		}
+		m.ObserveDB(db.DB, cfg.MySQL.Database)
+		checker.Add("mysql", true, db.DB.PingContext)
+		checker.Schema(func(ctx context.Context) (int, int, error) {
+			return db.SchemaVersion(ctx, db.DB)
+		})
+
+		// Apply pending schema migrations when requested
+		if cfg.Storage.AutoMigrate {
+			applied, err := db.MigrateUp(context.Background(), db.DB)
+			if err != nil {
+				fatal(logger, "migrating database failed", err)
+			}
+			logger.Info("applied migrations", "count", len(applied))
+		}
+
+		st = store.NewMySQLStore(db.DB)
 	}
-	defer db.Close()
 
-	// Initialize Redis connection
-	if err := db.InitRedis(); err != nil {
-		log.Fatalf("Failed to initialize Redis: %v", err)
+	// CACHE_BACKEND=redis keeps counters and cached pages in Redis, shared by
+	// every instance, and falls back to memory while Redis is down.
+	// CACHE_BACKEND=memory keeps them in process only.
+	local := cache.NewMemory(cfg.Cache.MemoryEntries)
+	var backend cache.Backend = local
+	var fallback *cache.Fallback
+	if cfg.UsesRedis() {
+		// Initialize Redis connection; the API keeps serving while it is down
+		err := db.InitRedis(cfg.Redis)
+		rdb = db.Redis
+		m.ObserveRedis(rdb)
+		tracing.InstrumentRedis(rdb)
+		checker.Add("redis", false, func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		})
+		fallback = cache.NewFallback(cache.NewRedis(rdb), local, logger)
+		if err != nil {
+			fallback.Degrade(err)
+		}
+		backend = fallback
 	}
-	defer db.CloseRedis()
+
+	// SEARCH_ENGINE=index answers searches from an in-process index instead of
+	// the store's own search
+	if cfg.Storage.SearchEngine == "index" {
+		st = newIndexedStore(st, logger)
+	}
+
+	handler := api.NewHandler(st, tokens, rdb, backend, logger, m)
+
+	// Seed the Redis tag autocomplete index from the store
+	if err := handler.RebuildTagIndex(context.Background()); err != nil {
+		logger.Warn("building tag autocomplete index failed", "error", err)
+	}
+
+	// Limit write requests in Redis, or in process while Redis is down or
+	// not used
+	limiter := ratelimit.New(cfg.RateLimit.Limits(), rdb, fallback, logger, m)
 
 	// Setup router
-	r := router.SetupRouter()
+	r, err := router.SetupRouter(handler, tokens, logger, m, checker, limiter, cfg.Server.Proxies())
+	if err != nil {
+		fatal(logger, "setting up routes failed", err)
+	}
+
+	// Shut down on SIGINT or SIGTERM; a second signal kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Get port from environment variable or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Write view counts and daily view stats kept in Redis to the store in
+	// the background, and watch for Redis to come back after an outage. The
+	// workers only stop once the server has drained, so their final flush
+	// includes the views of the last requests.
+	workers, stopWorkers := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	if fallback != nil {
+		// Values cached in Redis may have missed invalidations during the
+		// outage, and the autocomplete index missed tag writes
+		fallback.OnRecover(handler.InvalidateCachedQuestions)
+		fallback.OnRecover(func(ctx context.Context) {
+			if err := handler.RebuildTagIndex(ctx); err != nil {
+				logger.Warn("rebuilding tag autocomplete index failed", "error", err)
+			}
+		})
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			fallback.Run(workers, cfg.Cache.ProbeInterval)
+		}()
+	}
+	if rdb != nil {
+		flusher, err := counters.NewFlusher(rdb, st, cfg.Counters.FlushInterval, logger)
+		if err != nil {
+			fatal(logger, "creating view count flusher failed", err)
+		}
+		rollup := counters.NewRollup(rdb, st, cfg.Counters.RollupInterval, logger)
+		background.Add(2)
+		go func() {
+			defer background.Done()
+			flusher.Run(workers)
+		}()
+		go func() {
+			defer background.Done()
+			rollup.Run(workers)
+		}()
 	}
 
 	// Start server
-	log.Printf("Server starting on port %s...\n", port)
-	if err := r.Run(fmt.Sprintf(":%s", port)); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := newHTTPServer(cfg.Server, r)
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server starting", "port", cfg.Server.Port)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	failed := false
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down")
+	case err := <-serverErr:
+		logger.Error("server failed", "error", err)
+		failed = true
+	}
+	stop()
+
+	shutdown(logger, cfg, srv, checker, handler, stopWorkers, &background, stopTracing)
+	if failed {
+		os.Exit(1)
 	}
-} 
\ No newline at end of file
+}
+
+// newTokenManager creates the JWT manager from the JWT settings
+func newTokenManager(logger *slog.Logger, cfg config.JWTConfig) *auth.TokenManager {
+	secret := cfg.Secret
+	if secret == "" {
+		// Tokens signed with a random secret stop working when the server restarts
+		logger.Warn("JWT_SECRET is not set; using a random secret")
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			fatal(logger, "generating JWT secret failed", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+	return auth.NewTokenManager(secret, cfg.Expiration)
+}
+
+// fatal logs msg with err as an error and exits
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/questions/backend/internal/config"
+	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/search"
+	"github.com/questions/backend/internal/store"
+)
+
+const searchUsage = "usage: questions_backend search query <terms>"
+
+// newIndexedStore wraps st with a search index built from its questions. The
+// index is rebuilt whenever the process receives SIGHUP; a failed rebuild
+// keeps the previous index.
+func newIndexedStore(st store.Store, logger *slog.Logger) *store.IndexedStore {
+	indexed := store.NewIndexedStore(st, search.NewIndex(), logger)
+	if err := rebuildIndex(context.Background(), logger, indexed); err != nil {
+		fatal(logger, "building search index failed", err)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logger.Info("received SIGHUP; rebuilding search index")
+			if err := rebuildIndex(context.Background(), logger, indexed); err != nil {
+				logger.Error("rebuilding search index failed; serving the previous index", "error", err)
+			}
+		}
+	}()
+
+	return indexed
+}
+
+// rebuildIndex re-indexes every question and logs the result. The index is
+// only replaced when every question could be read.
+func rebuildIndex(ctx context.Context, logger *slog.Logger, indexed *store.IndexedStore) error {
+	start := time.Now()
+	n, err := indexed.Rebuild(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info("indexed questions", "questions", n, "terms", indexed.Index().Terms(),
+		"duration", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// runSearch implements the "search" subcommand. It builds an index from the
+// database to check tokenization and ranking; running servers rebuild their
+// own index on SIGHUP.
+func runSearch(cfg *config.Config, logger *slog.Logger, args []string) {
+	if len(args) < 2 || args[0] != "query" {
+		log.Fatal(searchUsage)
+	}
+
+	if err := db.InitMySQL(cfg.MySQL); err != nil {
+		fatal(logger, "initializing MySQL failed", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	indexed := store.NewIndexedStore(store.NewMySQLStore(db.DB), search.NewIndex(), logger)
+	if err := rebuildIndex(ctx, logger, indexed); err != nil {
+		fatal(logger, "building search index failed", err)
+	}
+
+	questions, err := indexed.ListQuestions(ctx, store.ListQuestionsParams{
+		Search: strings.Join(args[1:], " "),
+		Sort:   store.SortRelevance,
+		Limit:  10,
+	})
+	if err != nil {
+		fatal(logger, "searching failed", err)
+	}
+	for _, q := range questions {
+		fmt.Printf("%8.3f  #%d  %s\n", q.Relevance, q.ID, q.Title)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/questions/backend/internal/api"
+	"github.com/questions/backend/internal/config"
+	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/health"
+)
+
+// newHTTPServer creates the API server with the configured timeouts, so slow
+// or idle clients cannot hold connections open indefinitely
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// shutdown stops the server in order within the shutdown timeout. It fails
+// readiness and keeps serving for the drain delay, stops accepting
+// connections and drains in-flight requests, waits for the work those
+// requests left running, stops the background workers, which flush once more,
+// closes Redis and MySQL, which that flush still needs, and finally exports
+// the spans of all of it with stopTracing.
+func shutdown(logger *slog.Logger, cfg *config.Config, srv *http.Server, checker *health.Checker, handler *api.Handler,
+	stopWorkers context.CancelFunc, workers *sync.WaitGroup, stopTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Load balancers polling /readyz stop routing here during the delay;
+	// it is shorter than the shutdown timeout
+	checker.Drain()
+	if cfg.Health.DrainDelay > 0 {
+		logger.Info("draining before closing connections", "delay", cfg.Health.DrainDelay)
+		time.Sleep(cfg.Health.DrainDelay)
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn("closing connections of requests still running", "error", err)
+		srv.Close()
+	}
+	if err := handler.Wait(ctx); err != nil {
+		logger.Warn("abandoning view counts still being written", "error", err)
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Warn("abandoning background workers", "error", ctx.Err())
+	}
+
+	if err := db.CloseRedis(); err != nil {
+		logger.Warn("closing Redis failed", "error", err)
+	}
+	if err := db.Close(); err != nil {
+		logger.Warn("closing MySQL failed", "error", err)
+	}
+	if err := stopTracing(ctx); err != nil {
+		logger.Warn("exporting the last spans failed", "error", err)
+	}
+	logger.Info("server stopped")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/questions/backend/internal/api"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/config"
+	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+const tagsUsage = "usage: questions_backend tags merge <alias> <canonical>|synonyms [tag]|normalize [-dry-run]"
+
+// runTags implements the "tags" subcommand for tag administration
+func runTags(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal(tagsUsage)
+	}
+
+	if err := db.InitMySQL(cfg.MySQL); err != nil {
+		log.Fatalf("Failed to initialize MySQL: %v", err)
+	}
+	defer db.Close()
+
+	st := store.NewMySQLStore(db.DB)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "merge":
+		if len(args) != 3 {
+			log.Fatal(tagsUsage)
+		}
+		alias, canonical := args[1], args[2]
+		if normalized, err := models.NormalizeTagName(canonical); err == nil {
+			canonical = normalized
+		}
+		if alias == canonical {
+			log.Fatal("Alias and canonical tag must differ")
+		}
+		if err := st.MergeTags(ctx, alias, canonical); errors.Is(err, store.ErrNotFound) {
+			log.Fatalf("Tag %q does not exist", canonical)
+		} else if err != nil {
+			log.Fatalf("Merge failed: %v", err)
+		}
+		fmt.Printf("Merged %q into %q\n", alias, canonical)
+		refreshTagIndex(ctx, cfg, st)
+
+	case "synonyms":
+		tagName := ""
+		if len(args) > 1 {
+			tagName = args[1]
+		}
+		synonyms, err := st.ListTagSynonyms(ctx, tagName)
+		if err != nil {
+			log.Fatalf("Failed to list synonyms: %v", err)
+		}
+		for _, synonym := range synonyms {
+			fmt.Printf("%s -> %s\n", synonym.Alias, synonym.TagName)
+		}
+
+	case "normalize":
+		dryRun := len(args) > 1 && args[1] == "-dry-run"
+		if normalizeTags(ctx, st, dryRun) > 0 && !dryRun {
+			refreshTagIndex(ctx, cfg, st)
+		}
+
+	default:
+		log.Fatal(tagsUsage)
+	}
+}
+
+// normalizeTags renames every tag to its normalized spelling, merging it into
+// an existing tag that already has that spelling. It returns the number of
+// tags changed.
+func normalizeTags(ctx context.Context, st store.Store, dryRun bool) int {
+	var names []string
+	params := store.ListTagsParams{Sort: store.TagSortName, Limit: 500}
+	for {
+		tags, err := st.ListTags(ctx, params)
+		if err != nil {
+			log.Fatalf("Failed to list tags: %v", err)
+		}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		if len(tags) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	changed := 0
+	for _, name := range names {
+		normalized, err := models.NormalizeTagName(name)
+		if err != nil {
+			fmt.Printf("Skipping %q: %v\n", name, err)
+			continue
+		}
+		if normalized == name {
+			continue
+		}
+		changed++
+		if dryRun {
+			fmt.Printf("Would normalize %q to %q\n", name, normalized)
+			continue
+		}
+
+		err = st.RenameTag(ctx, name, normalized)
+		if errors.Is(err, store.ErrConflict) {
+			err = st.MergeTags(ctx, name, normalized)
+			if err == nil {
+				fmt.Printf("Merged %q into %q\n", name, normalized)
+			}
+		} else if err == nil {
+			fmt.Printf("Renamed %q to %q\n", name, normalized)
+		}
+		if err != nil {
+			log.Fatalf("Failed to normalize %q: %v", name, err)
+		}
+	}
+
+	if changed == 0 {
+		fmt.Println("All tags are normalized")
+	}
+	return changed
+}
+
+// refreshTagIndex rebuilds the Redis tag autocomplete index and drops cached
+// question pages after tags changed, when Redis is reachable. Running servers
+// pick up merged tags in their search index on SIGHUP.
+func refreshTagIndex(ctx context.Context, cfg *config.Config, st store.Store) {
+	if err := db.InitRedis(cfg.Redis); err != nil {
+		log.Printf("Warning: Redis unavailable, tag autocomplete index not refreshed: %v", err)
+		return
+	}
+	defer db.CloseRedis()
+
+	handler := api.NewHandler(st, nil, db.Redis, cache.NewRedis(db.Redis), nil, nil)
+	if err := handler.RebuildTagIndex(ctx); err != nil {
+		log.Printf("Warning: failed to refresh tag autocomplete index: %v", err)
+	}
+	handler.InvalidateCachedQuestions(ctx)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/questions/backend/internal/config"
+	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+const usersUsage = "usage: questions_backend users role <username> user|moderator"
+
+// runUsers implements the "users" subcommand for account administration
+func runUsers(cfg *config.Config, args []string) {
+	if len(args) != 3 || args[0] != "role" {
+		log.Fatal(usersUsage)
+	}
+	username, role := args[1], args[2]
+	if !slices.Contains(models.Roles, role) {
+		log.Fatalf("Role must be one of %s", strings.Join(models.Roles, ", "))
+	}
+
+	if err := db.InitMySQL(cfg.MySQL); err != nil {
+		log.Fatalf("Failed to initialize MySQL: %v", err)
+	}
+	defer db.Close()
+
+	st := store.NewMySQLStore(db.DB)
+	ctx := context.Background()
+
+	user, err := st.GetUserByLogin(ctx, username)
+	if errors.Is(err, store.ErrNotFound) {
+		log.Fatalf("User %q does not exist", username)
+	} else if err != nil {
+		log.Fatalf("Failed to retrieve user: %v", err)
+	}
+	if err := st.SetUserRole(ctx, user.ID, role); err != nil {
+		log.Fatalf("Failed to set role: %v", err)
+	}
+	fmt.Printf("Set role of %q to %s\n", user.Username, role)
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// validAnswerSorts are the orderings accepted by the answer_sort/sort parameters
+var validAnswerSorts = map[string]bool{
+	store.AnswerSortScore: true, store.AnswerSortNewest: true, store.AnswerSortOldest: true,
+}
+
+// ListAnswers handles retrieving the answers to a question
+func (h *Handler) ListAnswers(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	question, err := h.questions.GetQuestion(ctx, questionID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question"})
+		return
+	}
+
+	answers, err := h.loadAnswers(ctx, question, c.DefaultQuery("sort", store.AnswerSortScore))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve answers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"answers": answers})
+}
+
+// CreateAnswer handles posting an answer to a question
+func (h *Handler) CreateAnswer(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	var req models.AnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Check if question exists
+	exists, err := h.questions.QuestionExists(ctx, questionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check question existence"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+
+	answerID, err := h.answers.CreateAnswer(ctx, models.Answer{
+		QuestionID: questionID,
+		AuthorID:   currentUser(c),
+		Content:    req.Content,
+	})
+	if err != nil {
+		h.log(ctx).Error("creating answer failed", "question_id", questionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create answer"})
+		return
+	}
+
+	h.invalidateQuestion(ctx, questionID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      answerID,
+		"message": "Answer created successfully",
+	})
+}
+
+// UpdateAnswer handles editing an answer; only its author may edit it
+func (h *Handler) UpdateAnswer(c *gin.Context) {
+	questionID, answerID, ok := parseAnswerPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.AnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	answer, ok := h.findAnswer(c, questionID, answerID)
+	if !ok {
+		return
+	}
+
+	if !h.authorizeAuthor(c, answer.AuthorID, "Only the author or a moderator can edit this answer") {
+		return
+	}
+
+	if err := h.answers.UpdateAnswer(ctx, answerID, req.Content); errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update answer"})
+		return
+	}
+
+	h.invalidateQuestion(ctx, questionID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Answer updated successfully"})
+}
+
+// AddAnswerComment handles adding a comment to an answer
+func (h *Handler) AddAnswerComment(c *gin.Context) {
+	questionID, answerID, ok := parseAnswerPath(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, ok := h.findAnswer(c, questionID, answerID); !ok {
+		return
+	}
+
+	_, err := h.comments.AddComment(c.Request.Context(), models.Comment{
+		QuestionID: questionID,
+		AnswerID:   &answerID,
+		Content:    req.Content,
+		AuthorID:   currentUser(c),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+		return
+	}
+
+	h.metrics.CommentAdded(true)
+	h.invalidateQuestion(c.Request.Context(), questionID)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment added successfully"})
+}
+
+// AcceptAnswer handles the asker marking an answer as accepted
+func (h *Handler) AcceptAnswer(c *gin.Context) {
+	questionID, answerID, ok := parseAnswerPath(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.findAnswer(c, questionID, answerID); !ok {
+		return
+	}
+
+	h.setAcceptedAnswer(c, questionID, answerID, true)
+}
+
+// UnacceptAnswer handles the asker withdrawing the acceptance of an answer
+func (h *Handler) UnacceptAnswer(c *gin.Context) {
+	questionID, answerID, ok := parseAnswerPath(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.findAnswer(c, questionID, answerID); !ok {
+		return
+	}
+
+	h.setAcceptedAnswer(c, questionID, answerID, false)
+}
+
+// setAcceptedAnswer accepts the answer, or withdraws its acceptance, after
+// checking that the signed-in user asked the question or is a moderator
+func (h *Handler) setAcceptedAnswer(c *gin.Context, questionID, answerID int64, accept bool) {
+	ctx := c.Request.Context()
+
+	question, err := h.questions.GetQuestion(ctx, questionID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question"})
+		return
+	}
+
+	if !h.authorizeAuthor(c, question.AuthorID, "Only the asker or a moderator can accept an answer") {
+		return
+	}
+
+	accepted := &answerID
+	message := "Answer accepted successfully"
+	if !accept {
+		// Withdrawing acceptance only applies to the currently accepted answer
+		if question.AcceptedAnswerID == nil || *question.AcceptedAnswerID != answerID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Answer is not accepted"})
+			return
+		}
+		accepted = nil
+		message = "Answer acceptance removed successfully"
+	}
+
+	if err := h.questions.SetAcceptedAnswer(ctx, questionID, accepted); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update accepted answer"})
+		return
+	}
+
+	h.invalidateQuestion(ctx, questionID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            message,
+		"accepted_answer_id": accepted,
+	})
+}
+
+// loadAnswers returns a question's answers in the requested order with their
+// comments attached and the accepted answer flagged
+func (h *Handler) loadAnswers(ctx context.Context, question models.Question, sort string) ([]models.Answer, error) {
+	if !validAnswerSorts[sort] {
+		sort = store.AnswerSortScore
+	}
+
+	answers, err := h.answers.ListAnswers(ctx, question.ID, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := h.comments.ListAnswerComments(ctx, question.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range answers {
+		answers[i].IsAccepted = question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == answers[i].ID
+		answers[i].Comments = comments[answers[i].ID]
+		if answers[i].Comments == nil {
+			answers[i].Comments = []models.Comment{}
+		}
+	}
+	return answers, nil
+}
+
+// findAnswer loads an answer and checks it belongs to the question, writing
+// an error response and returning false otherwise
+func (h *Handler) findAnswer(c *gin.Context, questionID, answerID int64) (models.Answer, bool) {
+	answer, err := h.answers.GetAnswer(c.Request.Context(), answerID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && answer.QuestionID != questionID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+		return answer, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve answer"})
+		return answer, false
+	}
+	return answer, true
+}
+
+// parseAnswerPath parses the :id and :answerId URL parameters, writing an
+// error response and returning false if either is invalid
+func parseAnswerPath(c *gin.Context) (int64, int64, bool) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return 0, 0, false
+	}
+	answerID, err := strconv.ParseInt(c.Param("answerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answer ID"})
+		return 0, 0, false
+	}
+	return questionID, answerID, true
+}
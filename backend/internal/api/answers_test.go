@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/questions/backend/internal/models"
+)
+
+func TestAnswerPermissions(t *testing.T) {
+	s := newTestServer(t)
+	askerID, asker := s.signIn("asker")
+	answererID, answerer := s.signIn("answerer")
+	_, bob := s.signIn("bob")
+	_, mod := s.signInModerator("mod")
+	ctx := context.Background()
+
+	questionID, err := s.store.CreateQuestion(ctx, models.QuestionCreateRequest{
+		Title: "Answered question", Content: "Who may change the answers?",
+	}, &askerID)
+	if err != nil {
+		t.Fatalf("creating question: %v", err)
+	}
+	orphanID, err := s.store.CreateQuestion(ctx, models.QuestionCreateRequest{
+		Title: "Orphaned question", Content: "Its asker deleted their account",
+	}, nil)
+	if err != nil {
+		t.Fatalf("creating question: %v", err)
+	}
+
+	// The answerer may edit an answer and the asker may accept it; moderators may do both
+	tests := []struct {
+		name     string
+		question int64
+		token    string
+		edit     int
+		accept   int
+	}{
+		{"anonymous", questionID, "", http.StatusUnauthorized, http.StatusUnauthorized},
+		{"another user", questionID, bob, http.StatusForbidden, http.StatusForbidden},
+		{"answerer", questionID, answerer, http.StatusOK, http.StatusForbidden},
+		{"asker", questionID, asker, http.StatusForbidden, http.StatusOK},
+		{"moderator", questionID, mod, http.StatusOK, http.StatusOK},
+		{"user on an author-less question", orphanID, bob, http.StatusForbidden, http.StatusForbidden},
+		{"moderator on an author-less question", orphanID, mod, http.StatusOK, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answerID, err := s.store.CreateAnswer(ctx, models.Answer{
+				QuestionID: tt.question, AuthorID: &answererID, Content: "Close it from the sender",
+			})
+			if err != nil {
+				t.Fatalf("creating answer: %v", err)
+			}
+			path := fmt.Sprintf("/questions/%d/answers/%d", tt.question, answerID)
+
+			if code := s.do(http.MethodPut, path, jsonBody{"content": "Edited answer"}, tt.token, nil); code != tt.edit {
+				t.Errorf("edit: status = %d, want %d", code, tt.edit)
+			}
+			if code := s.do(http.MethodPost, path+"/accept", nil, tt.token, nil); code != tt.accept {
+				t.Errorf("accept: status = %d, want %d", code, tt.accept)
+			}
+		})
+	}
+}
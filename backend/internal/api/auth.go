@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// Register handles creating a new account and signs the user in
+func (h *Handler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if strings.Contains(req.Username, "@") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username must not contain '@'"})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	user := models.User{Username: req.Username, Email: req.Email, PasswordHash: hash, Role: models.RoleUser}
+	user.ID, err = h.users.CreateUser(c.Request.Context(), user)
+	if errors.Is(err, store.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username or email already registered"})
+		return
+	} else if err != nil {
+		h.log(c.Request.Context()).Error("creating user failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+
+	h.respondWithToken(c, http.StatusCreated, user)
+}
+
+// Login handles signing in with a username or email and password
+func (h *Handler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.GetUserByLogin(c.Request.Context(), strings.TrimSpace(req.Username))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		return
+	}
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+		return
+	}
+
+	h.respondWithToken(c, http.StatusOK, user)
+}
+
+// Me handles retrieving the signed-in user
+func (h *Handler) Me(c *gin.Context) {
+	userID, _ := auth.CurrentUserID(c)
+
+	user, err := h.users.GetUserByID(c.Request.Context(), userID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// respondWithToken issues a token for the user and writes it with the user's profile
+func (h *Handler) respondWithToken(c *gin.Context, status int, user models.User) {
+	token, expiresAt, err := h.tokens.Issue(user.ID, user.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue token"})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"token":      token,
+		"expires_at": expiresAt,
+		"user":       user,
+	})
+}
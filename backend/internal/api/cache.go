@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// Cached question data expires after these TTLs even without invalidation.
+// View and like counts are not cached with it; they are read from their own
+// counters on every request.
+const (
+	questionCacheTTL = 5 * time.Minute
+	listCacheTTL     = 30 * time.Second
+)
+
+// Cache tags shared by many values
+const (
+	// listCacheTag tags every cached list page, so any question write drops them all
+	listCacheTag = "questions"
+	// tagNamesCacheTag tags every cached value that shows tag names, so tag
+	// merges and renames drop them
+	tagNamesCacheTag = "tag-names"
+)
+
+// questionCacheTag tags the cached detail of one question
+func questionCacheTag(questionID int64) string {
+	return fmt.Sprintf("question:%d", questionID)
+}
+
+// questionDetail is the cached part of a question detail response
+type questionDetail struct {
+	Question models.Question  `json:"question"`
+	Tags     []models.Tag     `json:"tags"`
+	Comments []models.Comment `json:"comments"`
+	Answers  []models.Answer  `json:"answers"`
+}
+
+// questionPage is the cached part of a question list response
+type questionPage struct {
+	Questions []models.Question      `json:"questions"`
+	Tags      map[int64][]models.Tag `json:"tags"`
+	Total     int                    `json:"total"` // only set when counted
+}
+
+// InvalidateCachedQuestions drops every cached question detail and list page,
+// e.g. after tags were merged or renamed outside the API
+func (h *Handler) InvalidateCachedQuestions(ctx context.Context) {
+	h.cache.Invalidate(ctx, listCacheTag, tagNamesCacheTag)
+}
+
+// CacheStats handles reporting cache hits and misses per kind of value, and
+// whether the cache has fallen back from Redis to memory
+func (h *Handler) CacheStats(c *gin.Context) {
+	response := gin.H{"cache": h.cache.Stats()}
+	if fallback, ok := h.counts.(*cache.Fallback); ok {
+		response["degraded"] = fallback.Degraded()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// fetchCached returns the value of key through the cache, calling load on a
+// miss; values are stored as JSON
+func fetchCached[T any](ctx context.Context, c *cache.Versioned, kind, key string, tags []string, ttl time.Duration,
+	load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	data, err := c.Fetch(ctx, kind, key, tags, ttl, func(ctx context.Context) ([]byte, error) {
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	})
+	if err != nil {
+		return value, err
+	}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// loadQuestionDetail returns a question with its tags, comments and answers
+// in the given answer order, or store.ErrNotFound
+func (h *Handler) loadQuestionDetail(ctx context.Context, questionID int64, answerSort string) (questionDetail, error) {
+	key := fmt.Sprintf("cache:question:%d:%s", questionID, answerSort)
+	tags := []string{questionCacheTag(questionID), tagNamesCacheTag}
+	return fetchCached(ctx, h.cache, "question", key, tags, questionCacheTTL, func(ctx context.Context) (questionDetail, error) {
+		var detail questionDetail
+		var err error
+		if detail.Question, err = h.questions.GetQuestion(ctx, questionID); err != nil {
+			return detail, err
+		}
+		if detail.Tags, err = h.questions.GetQuestionTags(ctx, questionID); err != nil {
+			return detail, fmt.Errorf("failed to get tags: %w", err)
+		}
+		if detail.Comments, err = h.comments.ListComments(ctx, questionID); err != nil {
+			return detail, fmt.Errorf("failed to get comments: %w", err)
+		}
+		if detail.Answers, err = h.loadAnswers(ctx, detail.Question, answerSort); err != nil {
+			return detail, fmt.Errorf("failed to get answers: %w", err)
+		}
+		return detail, nil
+	})
+}
+
+// loadQuestionPage returns one page of questions with their tags and, when
+// counted is set, the number of matching questions. Contents are truncated
+// for the list view.
+func (h *Handler) loadQuestionPage(ctx context.Context, params store.ListQuestionsParams, counted bool) (questionPage, error) {
+	key := listCacheKey(params, counted)
+	return fetchCached(ctx, h.cache, "questions", key, []string{listCacheTag, tagNamesCacheTag}, listCacheTTL, func(ctx context.Context) (questionPage, error) {
+		var page questionPage
+		var err error
+		if counted {
+			if page.Total, err = h.questions.CountQuestions(ctx, params); err != nil {
+				return page, fmt.Errorf("failed to count questions: %w", err)
+			}
+		}
+		if page.Questions, err = h.questions.ListQuestions(ctx, params); err != nil {
+			return page, err
+		}
+
+		ids := make([]int64, len(page.Questions))
+		for i := range page.Questions {
+			ids[i] = page.Questions[i].ID
+			page.Questions[i].Content = truncateContent(page.Questions[i].Content, 200)
+		}
+		if page.Tags, err = h.questions.GetTagsForQuestions(ctx, ids); err != nil {
+			return page, fmt.Errorf("failed to get tags: %w", err)
+		}
+		return page, nil
+	})
+}
+
+// listCacheKey derives the cache key of a list page from its normalized
+// params, so tag order and spelling do not split the cache
+func listCacheKey(params store.ListQuestionsParams, counted bool) string {
+	params.Tags = sortedCopy(params.Tags)
+	params.ExcludeTags = sortedCopy(params.ExcludeTags)
+	data, _ := json.Marshal(struct {
+		Params  store.ListQuestionsParams
+		Counted bool
+	}{params, counted})
+	sum := sha256.Sum256(data)
+	return "cache:questions:" + hex.EncodeToString(sum[:16])
+}
+
+// sortedCopy returns a sorted copy of names
+func sortedCopy(names []string) []string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return sorted
+}
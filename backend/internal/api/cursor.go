@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/store"
+)
+
+// errInvalidCursor is returned for cursors that are malformed or badly signed
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursorSignatureSize is how many bytes of the HMAC a cursor carries
+const cursorSignatureSize = 16
+
+// listCursor is the decoded form of the opaque cursor returned as next_cursor.
+// It records the sort and the query it was issued for, so a cursor always
+// continues the list it came from.
+type listCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Query string `json:"q"` // see cursorQuery
+	Value string `json:"v"` // the sort field of the last question, see cursorValue
+	ID    int64  `json:"i"`
+}
+
+// newCursorKey derives the cursor signing key from the JWT secret. Without a
+// token manager it is random, so cursors do not survive a restart.
+func newCursorKey(tokens *auth.TokenManager) []byte {
+	if tokens != nil {
+		return tokens.DeriveKey("question-list-cursor")
+	}
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic("failed to generate cursor key: " + err.Error())
+	}
+	return key
+}
+
+// encodeCursor returns the signed cursor positioned after q in the given sort
+// of the list selected by params
+func (h *Handler) encodeCursor(sort, order string, params store.ListQuestionsParams, q store.QuestionCursor) string {
+	payload, _ := json.Marshal(listCursor{
+		Sort: sort, Order: order, Query: cursorQuery(params), Value: cursorValue(sort, q), ID: q.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(h.signCursor(payload))
+}
+
+// decodeCursor verifies a cursor and returns its sort, order and position
+func (h *Handler) decodeCursor(token string) (listCursor, store.QuestionCursor, error) {
+	var cursor listCursor
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return cursor, store.QuestionCursor{}, errInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, store.QuestionCursor{}, errInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, h.signCursor(payload)) {
+		return cursor, store.QuestionCursor{}, errInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cursor); err != nil {
+		return cursor, store.QuestionCursor{}, errInvalidCursor
+	}
+
+	position := store.QuestionCursor{ID: cursor.ID}
+	switch cursor.Sort {
+	case "created_at", "updated_at":
+		var t time.Time
+		t, err = time.Parse(time.RFC3339Nano, cursor.Value)
+		if cursor.Sort == "created_at" {
+			position.CreatedAt = t
+		} else {
+			position.UpdatedAt = t
+		}
+	case "like_count", "view_count":
+		var n int
+		n, err = strconv.Atoi(cursor.Value)
+		if cursor.Sort == "like_count" {
+			position.LikeCount = n
+		} else {
+			position.ViewCount = n
+		}
+	case store.SortRelevance:
+		position.Relevance, err = strconv.ParseFloat(cursor.Value, 64)
+	default:
+		err = errInvalidCursor
+	}
+	if err != nil || (cursor.Order != "asc" && cursor.Order != "desc") {
+		return cursor, store.QuestionCursor{}, errInvalidCursor
+	}
+	return cursor, position, nil
+}
+
+// signCursor returns the truncated HMAC of a cursor payload
+func (h *Handler) signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, h.cursorKey)
+	mac.Write(payload)
+	return mac.Sum(nil)[:cursorSignatureSize]
+}
+
+// cursorQuery hashes the search and filters of a list. Searches differing
+// only in case or spacing, and tag filters differing only in order, select the
+// same list.
+func cursorQuery(params store.ListQuestionsParams) string {
+	data, _ := json.Marshal(struct {
+		Search      string
+		SearchMode  string
+		Tags        []string
+		MatchAnyTag bool
+		ExcludeTags []string
+		Status      string
+	}{
+		Search:      strings.Join(strings.Fields(strings.ToLower(params.Search)), " "),
+		SearchMode:  params.SearchMode,
+		Tags:        sortedCopy(params.Tags),
+		MatchAnyTag: params.MatchAnyTag,
+		ExcludeTags: sortedCopy(params.ExcludeTags),
+		Status:      params.Status,
+	})
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// cursorValue formats the position's value of the sort field
+func cursorValue(sort string, q store.QuestionCursor) string {
+	switch sort {
+	case "updated_at":
+		return q.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "like_count":
+		return strconv.Itoa(q.LikeCount)
+	case "view_count":
+		return strconv.Itoa(q.ViewCount)
+	case store.SortRelevance:
+		return strconv.FormatFloat(q.Relevance, 'g', -1, 64)
+	}
+	return q.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
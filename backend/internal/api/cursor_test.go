@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/store"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	h := &Handler{cursorKey: newCursorKey(auth.NewTokenManager("test-secret", time.Hour))}
+	created := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	position := store.QuestionCursor{
+		ID: 42, CreatedAt: created, UpdatedAt: created.Add(time.Hour), LikeCount: 7, ViewCount: 99, Relevance: 1.25,
+	}
+	params := store.ListQuestionsParams{Search: "redis", Tags: []string{"go"}}
+
+	tests := []struct {
+		sort string
+		want store.QuestionCursor
+	}{
+		{"created_at", store.QuestionCursor{ID: 42, CreatedAt: created}},
+		{"updated_at", store.QuestionCursor{ID: 42, UpdatedAt: created.Add(time.Hour)}},
+		{"like_count", store.QuestionCursor{ID: 42, LikeCount: 7}},
+		{"view_count", store.QuestionCursor{ID: 42, ViewCount: 99}},
+		{store.SortRelevance, store.QuestionCursor{ID: 42, Relevance: 1.25}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			token := h.encodeCursor(tt.sort, "asc", params, position)
+			cursor, got, err := h.decodeCursor(token)
+			if err != nil {
+				t.Fatalf("decoding %q: %v", token, err)
+			}
+			if cursor.Sort != tt.sort || cursor.Order != "asc" || cursor.Query != cursorQuery(params) {
+				t.Errorf("cursor = %+v, want sort %s, order asc and the params' query", cursor, tt.sort)
+			}
+			if !got.CreatedAt.Equal(tt.want.CreatedAt) || !got.UpdatedAt.Equal(tt.want.UpdatedAt) ||
+				got.ID != tt.want.ID || got.LikeCount != tt.want.LikeCount || got.ViewCount != tt.want.ViewCount ||
+				got.Relevance != tt.want.Relevance {
+				t.Errorf("position = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	h := &Handler{cursorKey: newCursorKey(auth.NewTokenManager("test-secret", time.Hour))}
+	other := &Handler{cursorKey: newCursorKey(auth.NewTokenManager("other-secret", time.Hour))}
+	valid := h.encodeCursor("created_at", "desc", store.ListQuestionsParams{}, store.QuestionCursor{ID: 1, CreatedAt: time.Now()})
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	// sign returns a correctly signed cursor with the given payload
+	sign := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json)) + "." +
+			base64.RawURLEncoding.EncodeToString(h.signCursor([]byte(json)))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"bad base64", "!!!." + signature},
+		{"tampered payload", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"created_at","o":"desc","q":"","v":"2024-01-01T00:00:00Z","i":1}`)) + "." + signature},
+		{"truncated signature", payload + "." + signature[:len(signature)-2]},
+		{"signed with another key", other.encodeCursor("created_at", "desc", store.ListQuestionsParams{}, store.QuestionCursor{ID: 1})},
+		{"unknown sort", sign(`{"s":"title","o":"desc","q":"","v":"x","i":1}`)},
+		{"unknown order", sign(`{"s":"like_count","o":"up","q":"","v":"3","i":1}`)},
+		{"malformed value", sign(`{"s":"like_count","o":"desc","q":"","v":"many","i":1}`)},
+		{"unknown field", sign(`{"s":"like_count","o":"desc","q":"","v":"3","i":1,"x":true}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := h.decodeCursor(tt.token); err == nil {
+				t.Errorf("decodeCursor(%q) succeeded, want an error", tt.token)
+			}
+		})
+	}
+}
+
+func TestCursorQuery(t *testing.T) {
+	base := store.ListQuestionsParams{Search: "Redis  pipelines", Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}}
+
+	same := []store.ListQuestionsParams{
+		{Search: "redis pipelines", Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}},
+		{Search: " REDIS pipelines ", Tags: []string{"redis", "go"}, ExcludeTags: []string{"python"}},
+		// Sort and position do not select the list
+		{Search: "redis pipelines", Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}, Sort: "like_count", Limit: 50, Offset: 10},
+	}
+	for _, params := range same {
+		if cursorQuery(params) != cursorQuery(base) {
+			t.Errorf("cursorQuery(%+v) differs from cursorQuery(%+v)", params, base)
+		}
+	}
+
+	different := []store.ListQuestionsParams{
+		{Search: "redis", Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}},
+		{Search: "redis pipelines", SearchMode: store.SearchModeBoolean, Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}},
+		{Search: "redis pipelines", Tags: []string{"go"}, ExcludeTags: []string{"python"}},
+		{Search: "redis pipelines", Tags: []string{"go", "redis"}, MatchAnyTag: true, ExcludeTags: []string{"python"}},
+		{Search: "redis pipelines", Tags: []string{"go", "redis"}},
+		{Search: "redis pipelines", Tags: []string{"go", "redis"}, ExcludeTags: []string{"python"}, Status: store.QuestionStatusAnswered},
+	}
+	for _, params := range different {
+		if cursorQuery(params) == cursorQuery(base) {
+			t.Errorf("cursorQuery(%+v) equals cursorQuery(%+v)", params, base)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/counters"
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/metrics"
+	"github.com/questions/backend/internal/store"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer starts the spans of the handlers' own steps; requests, queries and
+// Redis commands are traced by their instrumentation
+var tracer = otel.Tracer("github.com/questions/backend/internal/api")
+
+// Handler serves the question API using injected stores
+type Handler struct {
+	questions store.QuestionStore
+	revisions store.RevisionStore
+	tags      store.TagStore
+	stats     store.StatsStore
+	comments  store.CommentStore
+	answers   store.AnswerStore
+	likes     store.LikeStore
+	users     store.UserStore
+
+	tokens *auth.TokenManager
+
+	// logger logs work outside requests; requests log to the logger their
+	// context carries
+	logger *slog.Logger
+
+	// metrics counts business events; nil counts nothing
+	metrics *metrics.Metrics
+
+	// cache holds question details and list pages
+	cache *cache.Versioned
+
+	// counts caches view/like counters and deduplicates views when views
+	// cannot be tracked
+	counts cache.Counter
+
+	// cursorKey signs the keyset pagination cursors of the question list
+	cursorKey []byte
+
+	// views buffers views for the counter flusher and records daily
+	// analytics; nil without Redis, and views then go to the store directly
+	views *counters.Tracker
+
+	// redis backs tag autocomplete; nil falls back to the store
+	redis *redis.Client
+
+	// background tracks work requests leave running after they respond
+	background sync.WaitGroup
+}
+
+// NewHandler creates a Handler backed by the given store, token manager,
+// optional Redis client and the backend caching values and counters. A nil
+// backend caches in process, a nil logger logs to the default logger and nil
+// metrics record nothing.
+func NewHandler(s store.Store, tokens *auth.TokenManager, rdb *redis.Client, backend cache.Backend, logger *slog.Logger,
+	m *metrics.Metrics) *Handler {
+	if backend == nil {
+		backend = cache.NewMemory(cache.DefaultMemoryEntries)
+	}
+	logger = logging.OrDefault(logger)
+	var views *counters.Tracker
+	if rdb != nil {
+		views = counters.NewTracker(rdb)
+	}
+	versioned := cache.NewVersioned(backend, logger)
+	m.ObserveCache(versioned, backend)
+	return &Handler{
+		questions: s,
+		revisions: s,
+		tags:      s,
+		stats:     s,
+		comments:  s,
+		answers:   s,
+		likes:     s,
+		users:     s,
+		tokens:    tokens,
+		logger:    logger,
+		metrics:   m,
+		cache:     versioned,
+		counts:    backend,
+		cursorKey: newCursorKey(tokens),
+		views:     views,
+		redis:     rdb,
+	}
+}
+
+// log returns the logger of ctx, which carries the request ID for requests,
+// or else the handler's logger
+func (h *Handler) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, h.logger)
+}
+
+// goBackground runs fn in its own goroutine, tracked so shutdown can wait for it
+func (h *Handler) goBackground(fn func()) {
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+		fn()
+	}()
+}
+
+// Wait blocks until the background work started by requests has finished or
+// ctx is done. Call it once the server no longer accepts requests.
+func (h *Handler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// currentUser returns the signed-in user's ID, or nil for anonymous requests
+func currentUser(c *gin.Context) *int64 {
+	if id, ok := auth.CurrentUserID(c); ok {
+		return &id
+	}
+	return nil
+}
+
+// isModerator reports whether the user may manage other users' posts. The
+// role is read from the store on every check, so revoking it takes effect
+// immediately.
+func (h *Handler) isModerator(ctx context.Context, userID int64) (bool, error) {
+	user, err := h.users.GetUserByID(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return user.IsModerator(), nil
+}
+
+// RequireModerator returns middleware that rejects requests unless the
+// signed-in user is a moderator; it must run after auth.RequireUser
+func (h *Handler) RequireModerator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.CurrentUserID(c)
+		moderator, err := h.isModerator(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+			return
+		}
+		if !moderator {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Moderator role required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// invalidateQuestion drops the cached detail of a question and every cached
+// list page after a write, even if ctx is canceled
+func (h *Handler) invalidateQuestion(ctx context.Context, questionID int64) {
+	h.cache.Invalidate(context.WithoutCancel(ctx), questionCacheTag(questionID), listCacheTag)
+}
+
+// invalidateLists drops every cached list page after a new question is
+// written, even if ctx is canceled
+func (h *Handler) invalidateLists(ctx context.Context) {
+	h.cache.Invalidate(context.WithoutCancel(ctx), listCacheTag)
+}
+
+// redisAvailable reports whether Redis is configured and the cache backend
+// has not fallen back from it, so Redis-only features are worth trying
+func (h *Handler) redisAvailable() bool {
+	if h.redis == nil {
+		return false
+	}
+	fallback, ok := h.counts.(*cache.Fallback)
+	return !ok || !fallback.Degraded()
+}
+
+// purgeQuestion drops every key kept for a deleted question: its counters,
+// today's visitors, and in Redis its unflushed views and daily visitor
+// counts. It also invalidates the question's cached detail and the cached lists.
+func (h *Handler) purgeQuestion(ctx context.Context, questionID int64) {
+	ctx = context.WithoutCancel(ctx)
+	h.invalidateQuestion(ctx, questionID)
+
+	keys := []string{
+		countKey(questionID, "views"),
+		countKey(questionID, "likes"),
+		counters.VisitorsKey(questionID, time.Now()),
+	}
+	if err := h.counts.Delete(ctx, keys...); err != nil {
+		h.log(ctx).Error("deleting question counters failed", "question_id", questionID, "error", err)
+	}
+	if h.views != nil && h.redisAvailable() {
+		if err := h.views.Purge(ctx, questionID, time.Now()); err != nil {
+			h.log(ctx).Error("purging question views failed", "question_id", questionID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// testServer serves the API routes from an in-memory store, without Redis
+type testServer struct {
+	t      *testing.T
+	engine *gin.Engine
+	store  *store.MemoryStore
+	tokens *auth.TokenManager
+}
+
+// newTestServer creates a testServer with an empty store
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	tokens := auth.NewTokenManager("test-secret", time.Hour)
+	h := NewHandler(st, tokens, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	// Wait for view counts incremented in the background
+	t.Cleanup(func() { h.Wait(context.Background()) })
+
+	engine := gin.New()
+	engine.Use(auth.Middleware(tokens))
+	questions := engine.Group("/questions")
+	questions.GET("", h.GetQuestions)
+	questions.GET("/:id", h.GetQuestion)
+	questions.POST("", h.CreateQuestion)
+	questions.PATCH("/:id", auth.RequireUser(), h.UpdateQuestion)
+	questions.DELETE("/:id", auth.RequireUser(), h.DeleteQuestion)
+	questions.GET("/:id/revisions", h.ListRevisions)
+	questions.GET("/:id/revisions/:rev/diff", h.RevisionDiff)
+	questions.POST("/:id/revisions/:rev/rollback", auth.RequireUser(), h.RollbackRevision)
+	questions.GET("/:id/stats", auth.RequireUser(), h.GetQuestionStats)
+	questions.POST("/:id/comments", h.AddComment)
+	questions.POST("/:id/answers", h.CreateAnswer)
+	questions.PUT("/:id/answers/:answerId", auth.RequireUser(), h.UpdateAnswer)
+	questions.POST("/:id/answers/:answerId/accept", auth.RequireUser(), h.AcceptAnswer)
+	questions.POST("/:id/like", h.LikeQuestion)
+
+	return &testServer{t: t, engine: engine, store: st, tokens: tokens}
+}
+
+// do serves a request with body encoded as JSON, signed in with token unless
+// it is empty, and decodes the JSON response into out unless it is nil
+func (s *testServer) do(method, path string, body interface{}, token string, out interface{}) int {
+	s.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("encoding request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.engine.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: decoding response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// signIn creates a user and returns their ID and a token for them
+func (s *testServer) signIn(username string) (int64, string) {
+	s.t.Helper()
+	ctx := context.Background()
+	id, err := s.store.CreateUser(ctx, models.User{Username: username, Email: username + "@example.com", Role: models.RoleUser})
+	if err != nil {
+		s.t.Fatalf("creating user: %v", err)
+	}
+	token, _, err := s.tokens.Issue(id, username)
+	if err != nil {
+		s.t.Fatalf("issuing token: %v", err)
+	}
+	return id, token
+}
+
+// signInModerator creates a moderator and returns their ID and a token for them
+func (s *testServer) signInModerator(username string) (int64, string) {
+	s.t.Helper()
+	id, token := s.signIn(username)
+	if err := s.store.SetUserRole(context.Background(), id, models.RoleModerator); err != nil {
+		s.t.Fatalf("granting moderator role: %v", err)
+	}
+	return id, token
+}
+
+// createQuestion stores a question with tags directly and returns its ID
+func (s *testServer) createQuestion(title string, tags ...string) int64 {
+	s.t.Helper()
+	id, err := s.store.CreateQuestion(context.Background(), models.QuestionCreateRequest{
+		Title:    title,
+		Content:  "Content of " + title,
+		TagNames: tags,
+	}, nil)
+	if err != nil {
+		s.t.Fatalf("creating question: %v", err)
+	}
+	return id
+}
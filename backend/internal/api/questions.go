@@ -2,30 +2,50 @@ package api
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/counters"
+	"github.com/questions/backend/internal/logging"
 	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// maxTagFilters bounds how many tags a list request may include or exclude
+const maxTagFilters = 10
+
 // GetQuestions handles retrieving all questions with pagination, sorting, and filtering
-func GetQuestions(c *gin.Context) {
+func (h *Handler) GetQuestions(c *gin.Context) {
 	// Parse query parameters
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
 	sort := c.DefaultQuery("sort", "created_at")
 	order := c.DefaultQuery("order", "desc")
-	tag := c.Query("tag")
+	tags := tagList(append(c.QueryArray("tags"), c.QueryArray("tag")...))
+	excludeTags := tagList(c.QueryArray("-tag"))
+	matchAny := c.Query("match") == "any"
 	search := c.Query("search")
+	searchMode := c.Query("search_mode")
+	status := c.Query("status")
+	// Any cursor parameter, even an empty one, switches to keyset pagination
+	cursorToken, cursorMode := c.GetQuery("cursor")
+
+	// Log request parameters for debugging; the search and tags are user input
+	h.log(c.Request.Context()).Debug("listing questions", "page", pageStr, "limit", limitStr, "sort", sort, "order", order,
+		"cursor", cursorMode, logging.Args(search, tags, excludeTags))
 
-	// Log request parameters for debugging
-	fmt.Printf("GetQuestions called with params: page=%s, limit=%s, sort=%s, order=%s, tag=%s, search=%s\n",
-		pageStr, limitStr, sort, order, tag, search)
+	if len(tags) > maxTagFilters || len(excludeTags) > maxTagFilters {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d tags can be included or excluded", maxTagFilters)})
+		return
+	}
 
 	// Validate and adjust pagination
 	page, _ := strconv.Atoi(pageStr)
@@ -40,40 +60,33 @@ func GetQuestions(c *gin.Context) {
 	// Calculate offset
 	offset := (page - 1) * limit
 
-	// Construct base query
-	baseQuery := "SELECT q.id, q.title, q.content, q.created_at, q.updated_at, q.like_count, q.view_count FROM questions q"
-	countQuery := "SELECT COUNT(*) FROM questions q"
-
-	// Add joins and filters
-	var args []interface{}
-	var whereClause string
-
-	if tag != "" {
-		baseQuery += " JOIN question_tags qt ON q.id = qt.question_id JOIN tags t ON qt.tag_id = t.id"
-		countQuery += " JOIN question_tags qt ON q.id = qt.question_id JOIN tags t ON qt.tag_id = t.id"
-		whereClause = " WHERE t.name = ?"
-		args = append(args, tag)
-	}
-
-	if search != "" {
-		if whereClause == "" {
-			whereClause = " WHERE"
-		} else {
-			whereClause += " AND"
+	// A cursor continues the list it was issued for, whatever sort and order
+	// the request asks for; its query is checked once the tags are resolved
+	var cursor listCursor
+	var after *store.QuestionCursor
+	if cursorToken != "" {
+		var position store.QuestionCursor
+		var err error
+		cursor, position, err = h.decodeCursor(cursorToken)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
 		}
-		whereClause += " (q.title LIKE ? OR q.content LIKE ?)"
-		args = append(args, "%"+search+"%", "%"+search+"%")
+		sort, order = cursor.Sort, cursor.Order
+		after = &position
+	}
+	if cursorMode {
+		offset = 0
 	}
 
-	baseQuery += whereClause
-	countQuery += whereClause
-
-	// Add order and pagination
+	// Validate ordering
 	validSortFields := map[string]bool{
 		"created_at": true, "updated_at": true, "like_count": true, "view_count": true,
+		store.SortRelevance: true,
 	}
 
-	if !validSortFields[sort] {
+	// Relevance only exists for searches
+	if !validSortFields[sort] || (sort == store.SortRelevance && search == "") {
 		sort = "created_at"
 	}
 
@@ -81,100 +94,143 @@ func GetQuestions(c *gin.Context) {
 		order = "desc"
 	}
 
-	baseQuery += fmt.Sprintf(" ORDER BY q.%s %s LIMIT ? OFFSET ?", sort, order)
-	args = append(args, limit, offset)
+	// Validate answer status filter
+	validStatuses := map[string]bool{
+		store.QuestionStatusUnanswered: true, store.QuestionStatusAnswered: true, store.QuestionStatusAccepted: true,
+	}
+
+	if !validStatuses[status] {
+		status = ""
+	}
 
-	// Add debug print statements
-	fmt.Printf("Debug - Base SQL Query: %s\n", baseQuery)
-	fmt.Printf("Debug - Count SQL Query: %s\n", countQuery)
-	fmt.Printf("Debug - Args: %v\n", args)
+	// An unknown search mode falls back to detecting it from the query
+	if searchMode != store.SearchModeNatural && searchMode != store.SearchModeBoolean {
+		searchMode = ""
+	}
+
+	params := store.ListQuestionsParams{
+		Tags:        tags,
+		MatchAnyTag: matchAny,
+		ExcludeTags: excludeTags,
+		Search:      search,
+		SearchMode:  searchMode,
+		Status:      status,
+		Sort:        sort,
+		Order:       order,
+		Limit:       limit,
+		Offset:      offset,
+		After:       after,
+	}
+	if cursorMode {
+		// One extra row tells whether another page follows
+		params.Limit = limit + 1
+	}
 
-	// Query for total count
-	var total int
-	err := db.DB.QueryRow(countQuery, args[:len(args)-2]...).Scan(&total)
+	ctx := c.Request.Context()
+
+	// Filter on canonical tag names so aliases and other spellings match too
+	var err error
+	if params.Tags, err = h.canonicalTags(ctx, tags); err == nil {
+		params.ExcludeTags, err = h.canonicalTags(ctx, excludeTags)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count questions"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve tags"})
+		return
+	}
+
+	// A cursor from another search or filter would skip or repeat rows
+	if after != nil && cursor.Query != cursorQuery(params) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cursor was issued for a different search or filter"})
 		return
 	}
 
-	// Query for questions
-	rows, err := db.DB.Query(baseQuery, args...)
+	// Load the page from the cache or the store; cursor pages skip the count
+	result, err := h.loadQuestionPage(ctx, params, !cursorMode)
 	if err != nil {
-		fmt.Printf("Query error: %v\nQuery: %s\nArgs: %v\n", err, baseQuery, args)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to retrieve questions: %v", err)})
+		h.log(ctx).Error("listing questions failed", "sort", params.Sort, "order", params.Order, "limit", params.Limit,
+			"offset", params.Offset, logging.Args(params.Search, params.Tags, params.ExcludeTags), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
 		return
 	}
-	defer rows.Close()
+	questions, total := result.Questions, result.Total
 
-	ctx := context.Background()
-	questions := []models.Question{}
-	for rows.Next() {
-		var q models.Question
-		if err := rows.Scan(&q.ID, &q.Title, &q.Content, &q.CreatedAt, &q.UpdatedAt, &q.LikeCount, &q.ViewCount); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan question"})
-			return
+	hasMore := offset+len(questions) < total
+	if cursorMode {
+		hasMore = len(questions) > limit
+		if hasMore {
+			questions = questions[:limit]
 		}
+	}
 
-		// Get the latest counts from Redis
-		redisViewCount := getCountFromRedis(ctx, q.ID, "views")
-		redisLikeCount := getCountFromRedis(ctx, q.ID, "likes")
-
-		// Update with Redis counts if available
-		if redisViewCount > 0 {
-			q.ViewCount = redisViewCount
-		}
-		if redisLikeCount > 0 {
-			q.LikeCount = redisLikeCount
-		}
+	// The cursor is taken before counts are refreshed from Redis, since the
+	// store orders by its own values
+	var nextCursor interface{}
+	if hasMore && len(questions) > 0 {
+		nextCursor = h.encodeCursor(sort, order, params, store.CursorOf(questions[len(questions)-1]))
+	}
 
-		questions = append(questions, q)
+	// Load the latest counts of the whole page at once
+	ids := make([]int64, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].ID
 	}
+	counts := h.loadCounts(ctx, ids)
 
-	// Get tags for each question and store in a map
-	questionTags := make(map[int64][]models.Tag)
+	questionTags := make(map[int64][]models.Tag, len(questions))
 	for i, question := range questions {
-		tags, err := getQuestionTags(question.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
-			return
+		if count, ok := counts[question.ID]; ok {
+			questions[i].ViewCount = count.Views
+			questions[i].LikeCount = count.Likes
 		}
-		questionTags[question.ID] = tags
-		questions[i].Content = truncateContent(questions[i].Content, 200) // Truncate for list view
+		questionTags[question.ID] = result.Tags[question.ID]
 	}
 
 	// Create custom questions response with both field naming conventions
 	customQuestions := make([]map[string]interface{}, len(questions))
 	for i, q := range questions {
 		customQuestions[i] = map[string]interface{}{
-			"id":          q.ID,
-			"title":       q.Title,
-			"content":     q.Content,
-			"created_at":  q.CreatedAt,
-			"updated_at":  q.UpdatedAt,
-			"like_count":  q.LikeCount,
-			"view_count":  q.ViewCount,
-			"likes_count": q.LikeCount,
-			"views_count": q.ViewCount,
+			"id":           q.ID,
+			"title":        q.Title,
+			"content":      q.Content,
+			"author_id":    q.AuthorID,
+			"created_at":   q.CreatedAt,
+			"updated_at":   q.UpdatedAt,
+			"like_count":   q.LikeCount,
+			"view_count":   q.ViewCount,
+			"likes_count":  q.LikeCount,
+			"views_count":  q.ViewCount,
+			"answer_count": q.AnswerCount,
+
+			"accepted_answer_id": q.AcceptedAnswerID,
+			"is_answered":        q.IsAnswered(),
+		}
+		if search != "" {
+			customQuestions[i]["relevance"] = q.Relevance
 		}
 	}
 
 	// Prepare pagination metadata
-	totalPages := (total + limit - 1) / limit
+	pagination := gin.H{
+		"limit":       limit,
+		"next_cursor": nextCursor,
+	}
+	if cursorMode {
+		pagination["has_more"] = hasMore
+	} else {
+		pagination["total"] = total
+		pagination["page"] = page
+		pagination["total_pages"] = (total + limit - 1) / limit
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"questions":     customQuestions,
 		"question_tags": questionTags,
-		"pagination": gin.H{
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": totalPages,
-		},
+		"pagination":    pagination,
 	})
 }
 
 // GetQuestion handles retrieving a single question by ID
-func GetQuestion(c *gin.Context) {
+func (h *Handler) GetQuestion(c *gin.Context) {
 	// Parse question ID from URL parameter
 	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -182,106 +238,59 @@ func GetQuestion(c *gin.Context) {
 		return
 	}
 
-	// Setup context for Redis operations
-	ctx := context.Background()
-
-	// Try to get question from Redis cache - skip cache for now to test our changes
-	/*
-		cacheKey := fmt.Sprintf("question:%d", questionID)
-		cachedQuestion, err := db.Redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			// Cache hit
-			var response models.QuestionResponse
-			if err := json.Unmarshal([]byte(cachedQuestion), &response); err == nil {
-				// Update view count in the background
-				go incrementViewCount(questionID)
-
-				// Get the latest counts from Redis
-				response.Question.ViewCount = getCountFromRedis(ctx, questionID, "views")
-				response.Question.LikeCount = getCountFromRedis(ctx, questionID, "likes")
-				response.Likes = response.Question.LikeCount
-
-				c.JSON(http.StatusOK, response)
-				return
-			}
-		}
-	*/
-
-	// Cache miss or error, fetch from database
-	query := `SELECT id, title, content, created_at, updated_at, like_count, view_count 
-			  FROM questions WHERE id = ?`
+	ctx := c.Request.Context()
 
-	var question models.Question
-	err = db.DB.QueryRow(query, questionID).Scan(
-		&question.ID, &question.Title, &question.Content,
-		&question.CreatedAt, &question.UpdatedAt,
-		&question.LikeCount, &question.ViewCount,
-	)
-
-	if err == sql.ErrNoRows {
+	// Get the question with its tags, comments and answers (ordered by score
+	// or age) from the cache or the store
+	answerSort := c.DefaultQuery("answer_sort", store.AnswerSortScore)
+	if !validAnswerSorts[answerSort] {
+		answerSort = store.AnswerSortScore
+	}
+	detail, err := h.loadQuestionDetail(ctx, questionID, answerSort)
+	if errors.Is(err, store.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
 		return
 	} else if err != nil {
+		h.log(ctx).Error("loading question failed", "question_id", questionID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question"})
 		return
 	}
-
-	// Get tags for this question
-	tags, err := getQuestionTags(questionID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
-		return
-	}
-
-	// Get comments for this question
-	comments, err := getQuestionComments(questionID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve comments"})
-		return
-	}
+	question, tags, comments, answers := detail.Question, detail.Tags, detail.Comments, detail.Answers
 
 	// Get the latest counts from Redis or initialize them
-	viewCount := getCountFromRedis(ctx, questionID, "views")
-	likeCount := getCountFromRedis(ctx, questionID, "likes")
-
-	// Update the question struct with the latest counts
-	question.ViewCount = viewCount
-	question.LikeCount = likeCount
-
-	// Track unique views by IP address with a time window of 24 hours
-	clientIP := c.ClientIP()
-	viewKey := fmt.Sprintf("question:%d:view:%s", questionID, clientIP)
-
-	// Check if this IP has already viewed the question in the last 24 hours
-	exists, err := db.Redis.Exists(ctx, viewKey).Result()
-	if err != nil {
-		fmt.Printf("Error checking view key: %v\n", err)
+	if count, ok := h.loadCounts(ctx, []int64{questionID})[questionID]; ok {
+		question.ViewCount = count.Views
+		question.LikeCount = count.Likes
 	}
 
-	// If the view doesn't exist in Redis, increment view count and set record
-	if exists == 0 {
-		// Set a record that this IP has viewed this question, with 24h expiry
-		db.Redis.Set(ctx, viewKey, 1, 24*time.Hour)
-
+	// Count the view once per client IP per day
+	if h.markViewed(ctx, questionID, c.ClientIP()) {
+		h.metrics.ViewRecorded()
 		// Increment view asynchronously
-		go incrementViewCount(questionID)
+		h.goBackground(func() { h.incrementViewCount(ctx, questionID) })
 	}
 
 	// Create a direct response with both field naming conventions
 	response := map[string]interface{}{
 		"question": map[string]interface{}{
-			"id":          question.ID,
-			"title":       question.Title,
-			"content":     question.Content,
-			"created_at":  question.CreatedAt,
-			"updated_at":  question.UpdatedAt,
-			"like_count":  question.LikeCount,
-			"view_count":  question.ViewCount,
-			"likes_count": question.LikeCount,
-			"views_count": question.ViewCount,
+			"id":           question.ID,
+			"title":        question.Title,
+			"content":      question.Content,
+			"author_id":    question.AuthorID,
+			"created_at":   question.CreatedAt,
+			"updated_at":   question.UpdatedAt,
+			"like_count":   question.LikeCount,
+			"view_count":   question.ViewCount,
+			"likes_count":  question.LikeCount,
+			"views_count":  question.ViewCount,
+			"answer_count": question.AnswerCount,
+
+			"accepted_answer_id": question.AcceptedAnswerID,
+			"is_answered":        question.IsAnswered(),
 		},
 		"tags":     tags,
 		"comments": comments,
+		"answers":  answers,
 		"likes":    question.LikeCount,
 	}
 
@@ -289,90 +298,171 @@ func GetQuestion(c *gin.Context) {
 }
 
 // CreateQuestion handles creating a new question
-func CreateQuestion(c *gin.Context) {
+func (h *Handler) CreateQuestion(c *gin.Context) {
 	var req models.QuestionCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Begin transaction
-	tx, err := db.DB.Begin()
+	tagNames, err := normalizeTagNames(req.TagNames)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer tx.Rollback()
+	req.TagNames = tagNames
 
-	// Insert question
-	result, err := tx.Exec(
-		"INSERT INTO questions (title, content) VALUES (?, ?)",
-		req.Title, req.Content,
-	)
+	questionID, err := h.questions.CreateQuestion(c.Request.Context(), req, currentUser(c))
 	if err != nil {
+		h.log(c.Request.Context()).Error("creating question failed", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create question"})
 		return
 	}
 
-	// Get the newly inserted question ID
-	questionID, err := result.LastInsertId()
+	h.metrics.QuestionCreated()
+
+	// Invalidate cache
+	h.invalidateLists(c.Request.Context())
+	h.refreshTags(c.Request.Context(), req.TagNames)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      questionID,
+		"message": "Question created successfully",
+	})
+}
+
+// UpdateQuestion handles editing a question. PUT replaces the title, content
+// and tags; PATCH changes only the fields present in the request, and an
+// explicit empty tags list clears the tags.
+func (h *Handler) UpdateQuestion(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get question ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
 		return
 	}
 
-	// Process tags
-	if len(req.TagNames) > 0 {
-		for _, tagName := range req.TagNames {
-			// Try to find existing tag or create a new one
-			var tagID int64
-			err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", tagName).Scan(&tagID)
-			if err == sql.ErrNoRows {
-				// Tag doesn't exist, create it
-				res, err := tx.Exec("INSERT INTO tags (name) VALUES (?)", tagName)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
-					return
-				}
-				tagID, err = res.LastInsertId()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tag ID"})
-					return
-				}
-			} else if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check tag existence"})
-				return
-			}
+	var req models.QuestionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-			// Associate tag with question
-			_, err = tx.Exec(
-				"INSERT INTO question_tags (question_id, tag_id) VALUES (?, ?)",
-				questionID, tagID,
-			)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to associate tag with question"})
-				return
-			}
+	update := store.QuestionUpdate{EditorID: currentUser(c)}
+	if c.Request.Method == http.MethodPut {
+		if req.Title == "" || req.Content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title and content are required"})
+			return
 		}
+		if req.TagNames == nil {
+			req.TagNames = []string{}
+		}
+	}
+	if req.Title != "" {
+		update.Title = &req.Title
+	}
+	if req.Content != "" {
+		update.Content = &req.Content
+	}
+	if req.TagNames != nil {
+		tagNames, err := normalizeTagNames(req.TagNames)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update.TagNames = &tagNames
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	ctx := c.Request.Context()
+
+	if !h.authorizeQuestionAuthor(c, questionID, "Only the author or a moderator can edit this question") {
 		return
 	}
+	previousTags := h.questionTagNames(ctx, questionID)
 
-	// Invalidate cache
-	db.Redis.Del(context.Background(), "questions:list")
+	if err := h.questions.UpdateQuestion(ctx, questionID, update); errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	} else if err != nil {
+		h.log(ctx).Error("updating question failed", "question_id", questionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update question"})
+		return
+	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":      questionID,
-		"message": "Question created successfully",
-	})
+	h.invalidateQuestion(ctx, questionID)
+	if update.TagNames != nil {
+		h.refreshTags(ctx, append(previousTags, *update.TagNames...))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question updated successfully"})
+}
+
+// DeleteQuestion handles removing a question together with its tag links,
+// answers, comments and likes
+func (h *Handler) DeleteQuestion(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	if !h.authorizeQuestionAuthor(c, questionID, "Only the author or a moderator can delete this question") {
+		return
+	}
+	tags := h.questionTagNames(c.Request.Context(), questionID)
+
+	if err := h.questions.DeleteQuestion(c.Request.Context(), questionID); errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	} else if err != nil {
+		h.log(c.Request.Context()).Error("deleting question failed", "question_id", questionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete question"})
+		return
+	}
+
+	h.purgeQuestion(c.Request.Context(), questionID)
+	h.refreshTags(c.Request.Context(), tags)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// authorizeQuestionAuthor loads a question and checks that the signed-in user
+// asked it or is a moderator, writing the error response and returning false
+// otherwise. Questions without an author can only be managed by moderators.
+func (h *Handler) authorizeQuestionAuthor(c *gin.Context, questionID int64, forbidden string) bool {
+	question, err := h.questions.GetQuestion(c.Request.Context(), questionID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question"})
+		return false
+	}
+
+	return h.authorizeAuthor(c, question.AuthorID, forbidden)
+}
+
+// authorizeAuthor checks that the signed-in user is authorID or a moderator,
+// writing the error response and returning false otherwise
+func (h *Handler) authorizeAuthor(c *gin.Context, authorID *int64, forbidden string) bool {
+	userID, _ := auth.CurrentUserID(c)
+	if authorID != nil && *authorID == userID {
+		return true
+	}
+
+	moderator, err := h.isModerator(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		return false
+	}
+	if !moderator {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbidden})
+		return false
+	}
+	return true
 }
 
 // AddComment handles adding a comment to a question
-func AddComment(c *gin.Context) {
+func (h *Handler) AddComment(c *gin.Context) {
 	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
@@ -388,9 +478,10 @@ func AddComment(c *gin.Context) {
 		return
 	}
 
+	ctx := c.Request.Context()
+
 	// Check if question exists
-	var exists bool
-	err = db.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM questions WHERE id = ?)", questionID).Scan(&exists)
+	exists, err := h.questions.QuestionExists(ctx, questionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check question existence"})
 		return
@@ -402,273 +493,271 @@ func AddComment(c *gin.Context) {
 	}
 
 	// Insert comment
-	_, err = db.DB.Exec(
-		"INSERT INTO comments (question_id, content) VALUES (?, ?)",
-		questionID, req.Content,
-	)
+	_, err = h.comments.AddComment(ctx, models.Comment{
+		QuestionID: questionID,
+		Content:    req.Content,
+		AuthorID:   currentUser(c),
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
 		return
 	}
 
+	h.metrics.CommentAdded(false)
+
 	// Invalidate cache
-	cacheKey := fmt.Sprintf("question:%d", questionID)
-	db.Redis.Del(context.Background(), cacheKey)
+	h.invalidateQuestion(ctx, questionID)
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Comment added successfully"})
 }
 
 // LikeQuestion handles toggling a like on a question (add or remove)
-func LikeQuestion(c *gin.Context) {
+func (h *Handler) LikeQuestion(c *gin.Context) {
 	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
 		return
 	}
 
-	fmt.Printf("LikeQuestion called for question ID: %d\n", questionID)
+	ctx := c.Request.Context()
 
 	// Check if question exists
-	var exists bool
-	err = db.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM questions WHERE id = ?)", questionID).Scan(&exists)
+	exists, err := h.questions.QuestionExists(ctx, questionID)
 	if err != nil {
-		fmt.Printf("Error checking if question exists: %v\n", err)
+		h.log(ctx).Error("checking question existence failed", "question_id", questionID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check question existence"})
 		return
 	}
 
 	if !exists {
-		fmt.Printf("Question ID %d not found\n", questionID)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
 		return
 	}
 
 	// Get client IP to identify the liker
 	clientIP := c.ClientIP()
-	fmt.Printf("Client IP: %s\n", clientIP)
 
-	// Check if this IP has already liked this question
-	var alreadyLiked bool
-	err = db.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM likes WHERE question_id = ? AND client_ip = ?)",
-		questionID, clientIP).Scan(&alreadyLiked)
+	liked, likeCount, err := h.likes.ToggleLike(ctx, questionID, currentUser(c), clientIP)
 	if err != nil {
-		fmt.Printf("Error checking like status in database: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check like status"})
+		h.log(ctx).Error("toggling like failed", "question_id", questionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle like"})
 		return
 	}
+	h.metrics.LikeToggled(liked)
 
-	// Begin transaction
-	tx, err := db.DB.Begin()
-	if err != nil {
-		fmt.Printf("Error beginning transaction: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction"})
-		return
-	}
-	defer tx.Rollback()
-
-	var action string
-
-	if !alreadyLiked {
-		// Not liked yet, add like
-		_, err = tx.Exec("INSERT INTO likes (question_id, client_ip) VALUES (?, ?)", questionID, clientIP)
-		if err != nil {
-			fmt.Printf("Error inserting like: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add like"})
-			return
-		}
-
-		// Update the like count in the questions table
-		_, err = tx.Exec("UPDATE questions SET like_count = like_count + 1 WHERE id = ?", questionID)
-		if err != nil {
-			fmt.Printf("Error updating like count: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update like count"})
-			return
-		}
-
+	action := "removed"
+	if liked {
 		action = "added"
-	} else {
-		// Already liked, remove like
-		_, err = tx.Exec("DELETE FROM likes WHERE question_id = ? AND client_ip = ?", questionID, clientIP)
-		if err != nil {
-			fmt.Printf("Error removing like: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove like"})
-			return
-		}
-
-		// Update the like count in the questions table (ensure it doesn't go below 0)
-		_, err = tx.Exec("UPDATE questions SET like_count = GREATEST(like_count - 1, 0) WHERE id = ?", questionID)
-		if err != nil {
-			fmt.Printf("Error updating like count: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update like count"})
-			return
-		}
-
-		action = "removed"
-	}
-
-	// Commit the transaction
-	if err := tx.Commit(); err != nil {
-		fmt.Printf("Error committing transaction: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
 	}
 
-	fmt.Printf("Successfully %s like for question ID: %d from IP: %s\n", action, questionID, clientIP)
+	h.log(ctx).Debug("like toggled", "question_id", questionID, "action", action, logging.ClientIP(clientIP))
 
-	// Get the updated like count
-	var likeCount int
-	err = db.DB.QueryRow("SELECT like_count FROM questions WHERE id = ?", questionID).Scan(&likeCount)
-	if err != nil {
-		fmt.Printf("Error getting updated like count: %v\n", err)
+	// Cached pages leave counts to the counters, so only the counter changes
+	if err := h.counts.SetCount(context.WithoutCancel(ctx), countKey(questionID, "likes"), int64(likeCount), countTTL); err != nil {
+		h.log(ctx).Error("caching like count failed", "question_id", questionID, "error", err)
 	}
 
-	// Update Redis with the new count
-	ctx := context.Background()
-	redisKey := fmt.Sprintf("question:%d:likes", questionID)
-	db.Redis.Set(ctx, redisKey, likeCount, 24*time.Hour)
-
-	// Invalidate question cache
-	cacheKey := fmt.Sprintf("question:%d", questionID)
-	db.Redis.Del(ctx, cacheKey)
-
 	c.JSON(http.StatusOK, gin.H{
 		"message":    fmt.Sprintf("Question like %s successfully", action),
-		"liked":      action == "added",
+		"liked":      liked,
 		"like_count": likeCount,
 	})
 }
 
-// Helper function to get tags for a question
-func getQuestionTags(questionID int64) ([]models.Tag, error) {
-	query := `
-		SELECT t.id, t.name 
-		FROM tags t
-		JOIN question_tags qt ON t.id = qt.tag_id
-		WHERE qt.question_id = ?
-	`
+// countTTL is how long counters loaded from the store stay cached
+const countTTL = 24 * time.Hour
 
-	rows, err := db.DB.Query(query, questionID)
+// Helper function to load the view and like counts of several questions. The
+// counters are read in one call; those missing are loaded from the store in
+// one query and cached in one call. Questions whose counts cannot be loaded
+// are left out of the result.
+func (h *Handler) loadCounts(ctx context.Context, questionIDs []int64) map[int64]store.QuestionCounts {
+	counts := make(map[int64]store.QuestionCounts, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return counts
+	}
+	ctx, span := tracer.Start(ctx, "load counts", trace.WithAttributes(attribute.Int("questions", len(questionIDs))))
+	defer span.End()
+
+	// cached[2*i] and cached[2*i+1] hold the views and likes of questionIDs[i]
+	keys := make([]string, 0, 2*len(questionIDs))
+	for _, id := range questionIDs {
+		keys = append(keys, countKey(id, "views"), countKey(id, "likes"))
+	}
+	cached, found, err := h.counts.Counts(ctx, keys...)
 	if err != nil {
-		return nil, err
+		h.log(ctx).Error("reading cached counts failed", "error", err)
+		cached, found = make([]int64, len(keys)), make([]bool, len(keys))
 	}
-	defer rows.Close()
 
-	var tags []models.Tag
-	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
-			return nil, err
+	var missing []int64
+	for i, id := range questionIDs {
+		if found[2*i] && found[2*i+1] {
+			counts[id] = store.QuestionCounts{Views: int(cached[2*i]), Likes: int(cached[2*i+1])}
+		} else {
+			missing = append(missing, id)
 		}
-		tags = append(tags, tag)
+	}
+	span.SetAttributes(attribute.Int("questions.uncached", len(missing)))
+	if len(missing) == 0 {
+		return counts
 	}
 
-	return tags, nil
-}
-
-// Helper function to get comments for a question
-func getQuestionComments(questionID int64) ([]models.Comment, error) {
-	query := `
-		SELECT id, question_id, content, created_at
-		FROM comments
-		WHERE question_id = ?
-		ORDER BY created_at DESC
-	`
-
-	rows, err := db.DB.Query(query, questionID)
+	stored, err := h.questions.GetCountsForQuestions(ctx, missing)
 	if err != nil {
-		return nil, err
+		h.log(ctx).Error("loading counts from store failed", "error", err)
+		return counts
 	}
-	defer rows.Close()
 
-	var comments []models.Comment
-	for rows.Next() {
-		var comment models.Comment
-		if err := rows.Scan(&comment.ID, &comment.QuestionID, &comment.Content, &comment.CreatedAt); err != nil {
-			return nil, err
+	initial := make(map[string]int64)
+	for i, id := range questionIDs {
+		count, ok := stored[id]
+		if !ok {
+			continue
 		}
-		comments = append(comments, comment)
+		// A cached counter is newer than the store, so only the missing half
+		// is taken from the store and cached. Counters a concurrent view
+		// created meanwhile are left alone.
+		if found[2*i] {
+			count.Views = int(cached[2*i])
+		} else {
+			initial[keys[2*i]] = int64(count.Views)
+		}
+		if found[2*i+1] {
+			count.Likes = int(cached[2*i+1])
+		} else {
+			initial[keys[2*i+1]] = int64(count.Likes)
+		}
+		counts[id] = count
 	}
-
-	return comments, nil
+	if err := h.counts.InitCounts(ctx, initial, countTTL); err != nil {
+		h.log(ctx).Error("caching counts failed", "error", err)
+	}
+	return counts
 }
 
-// Helper function to get count from Redis with fallback to database
-func getCountFromRedis(ctx context.Context, questionID int64, countType string) int {
-	// Try to get count from Redis first
-	redisKey := fmt.Sprintf("question:%d:%s", questionID, countType)
-	count, err := db.Redis.Get(ctx, redisKey).Int()
-	if err == nil {
-		// We have a value in Redis, use it
-		return count
-	}
+// countKey returns the key of a question's views or likes counter
+func countKey(questionID int64, countType string) string {
+	return fmt.Sprintf("question:%d:%s", questionID, countType)
+}
 
-	// No value in Redis or error, get from database
-	var dbCount int
-	var query string
-	if countType == "views" {
-		query = "SELECT view_count FROM questions WHERE id = ?"
-	} else if countType == "likes" {
-		query = "SELECT like_count FROM questions WHERE id = ?"
+// Helper function to record a view by a client IP in today's analytics,
+// reporting whether it is the IP's first view of the question today (UTC).
+// Visitors are counted with a HyperLogLog per question per day, so a new
+// visitor is occasionally taken for a returning one. Without Redis, views are
+// only deduplicated by the cache backend and left out of the analytics.
+func (h *Handler) markViewed(ctx context.Context, questionID int64, clientIP string) bool {
+	ctx, span := tracer.Start(ctx, "mark viewed")
+	defer span.End()
+
+	now := time.Now()
+	if h.views != nil && h.redisAvailable() {
+		first, err := h.views.RecordVisit(ctx, questionID, clientIP, now)
+		if err == nil {
+			return first
+		}
+		h.log(ctx).Error("recording view failed", "question_id", questionID, "error", err)
 	}
 
-	err = db.DB.QueryRow(query, questionID).Scan(&dbCount)
+	first, err := h.counts.AddUnique(ctx, counters.VisitorsKey(questionID, now), clientIP, counters.VisitorsTTL)
 	if err != nil {
-		fmt.Printf("Error getting %s count from database: %v\n", countType, err)
-		return 0
+		h.log(ctx).Error("deduplicating view failed", "question_id", questionID, "error", err)
+		return true
 	}
-
-	// Update Redis with the database value
-	db.Redis.Set(ctx, redisKey, dbCount, 24*time.Hour)
-	return dbCount
+	return first
 }
 
-// Helper function to increment view count
-func incrementViewCount(questionID int64) {
-	ctx := context.Background()
-	redisKey := fmt.Sprintf("question:%d:views", questionID)
-
-	// First check if the key exists in Redis
-	exists, err := db.Redis.Exists(ctx, redisKey).Result()
+// Helper function to increment view count, even if ctx is canceled
+func (h *Handler) incrementViewCount(ctx context.Context, questionID int64) {
+	// The span outlives the request's span, which ends once the response is sent
+	ctx, span := tracer.Start(context.WithoutCancel(ctx), "increment view count")
+	defer span.End()
+	logger := h.log(ctx).With("question_id", questionID)
+	key := countKey(questionID, "views")
+
+	// If the counter is missing, start it from the stored count unless a
+	// concurrent view does first
+	_, found, err := h.counts.Counts(ctx, key)
 	if err != nil {
-		fmt.Printf("Error checking if view key exists in Redis: %v\n", err)
-	}
-
-	// If key doesn't exist, get the current count from database first
-	if exists == 0 {
-		var dbCount int
-		err := db.DB.QueryRow("SELECT view_count FROM questions WHERE id = ?", questionID).Scan(&dbCount)
+		logger.Error("checking view counter failed", "error", err)
+	} else if !found[0] {
+		dbCount, _, err := h.questions.GetCounts(ctx, questionID)
 		if err != nil {
-			fmt.Printf("Failed to get view count from database: %v\n", err)
+			logger.Error("loading view count from store failed", "error", err)
 			dbCount = 0
 		}
-		// Initialize Redis with the database count
-		db.Redis.Set(ctx, redisKey, dbCount, 24*time.Hour)
+		h.counts.InitCounts(ctx, map[string]int64{key: int64(dbCount)}, countTTL)
 	}
 
-	// Now increment in Redis
-	newCount, err := db.Redis.Incr(ctx, redisKey).Result()
-	if err != nil {
-		fmt.Printf("Failed to increment view count in Redis: %v\n", err)
-		// Fallback to database update
-		_, err = db.DB.Exec("UPDATE questions SET view_count = view_count + 1 WHERE id = ?", questionID)
-		if err != nil {
-			fmt.Printf("Failed to increment view count in database: %v\n", err)
+	// Refreshing the TTL keeps the counter from expiring while it is ahead of the store
+	if _, err := h.counts.IncrBy(ctx, key, 1, countTTL); err != nil {
+		logger.Error("incrementing view counter failed", "error", err)
+	}
+
+	// Queue the view for the counter flusher, which writes buffered views to
+	// the store in batches
+	if h.views != nil && h.redisAvailable() {
+		err := h.views.QueueView(ctx, questionID)
+		if err == nil {
+			return
 		}
-		return
+		logger.Error("queueing view in Redis failed; writing it to the store", "error", err)
 	}
 
-	// Set expiration if this is a new key
-	if newCount == 1 {
-		db.Redis.Expire(ctx, redisKey, 24*time.Hour)
+	// Fallback to store update
+	if err := h.questions.IncrementViewCount(ctx, questionID); err != nil {
+		logger.Error("incrementing view count in store failed", "error", err)
 	}
+}
 
-	// Periodically update the database (e.g., every 5 views)
-	if newCount%5 == 0 {
-		_, err = db.DB.Exec("UPDATE questions SET view_count = ? WHERE id = ?", newCount, questionID)
+// normalizeTagNames normalizes the tag names of a question, dropping duplicates
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := models.NormalizeTagName(name)
 		if err != nil {
-			fmt.Printf("Failed to update view count in database: %v\n", err)
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// canonicalTags normalizes tag filter names and maps synonyms to their
+// canonical tags. Names that cannot be normalized are kept and simply match nothing.
+func (h *Handler) canonicalTags(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		if tag, err := models.NormalizeTagName(name); err == nil {
+			name = tag
+		}
+		normalized[i] = name
+	}
+	return h.tags.ResolveTags(ctx, normalized)
+}
+
+// tagList splits comma-separated tag query values into a list of distinct
+// names, dropping empty entries
+func tagList(values []string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				tags = append(tags, name)
+			}
 		}
 	}
+	return tags
 }
 
 // Helper function to truncate content
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// BenchmarkGetQuestions compares the store calls and Redis round trips of one
+// list page with the per-row access pattern the endpoint used before
+// batching. Cold runs start without cached counters, so every counter falls
+// back to the store; warm runs find them all in Redis. Cached pages are
+// dropped before every run in both. Redis runs in process and the store has
+// no latency, so ns/op mostly measures the batched runs' routing and JSON
+// encoding; compare the round trips instead.
+func BenchmarkGetQuestions(b *testing.B) {
+	const questions, limit = 1000, 100
+
+	ctx := context.Background()
+	st := &countingStore{Store: store.NewMemoryStore()}
+	seedQuestions(b, st, questions)
+
+	mr := miniredis.RunT(b)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	b.Cleanup(func() { rdb.Close() })
+	roundTrips := &countingHook{}
+	rdb.AddHook(roundTrips)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewHandler(st, nil, rdb, cache.NewRedis(rdb), logger, nil)
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/questions", h.GetQuestions)
+	url := fmt.Sprintf("/questions?limit=%d", limit)
+
+	strategies := []struct {
+		name string
+		run  func() error
+	}{
+		{"per-row", func() error { return perRowList(ctx, st, rdb, limit) }},
+		{"batched", func() error {
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			if rec.Code != http.StatusOK {
+				return fmt.Errorf("list returned %d: %s", rec.Code, rec.Body.String())
+			}
+			return nil
+		}},
+	}
+	for _, strategy := range strategies {
+		for _, cold := range []bool{true, false} {
+			name := strategy.name + "/warm"
+			if cold {
+				name = strategy.name + "/cold"
+			}
+			b.Run(name, func(b *testing.B) {
+				// Warm the counters once; warm runs then keep them
+				if err := strategy.run(); err != nil {
+					b.Fatal(err)
+				}
+
+				var calls, trips int64
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					if cold {
+						mr.FlushAll()
+					} else {
+						h.InvalidateCachedQuestions(ctx)
+					}
+					st.calls.Store(0)
+					roundTrips.count.Store(0)
+					b.StartTimer()
+
+					if err := strategy.run(); err != nil {
+						b.Fatal(err)
+					}
+					calls += st.calls.Load()
+					trips += roundTrips.count.Load()
+				}
+				b.ReportMetric(float64(calls)/float64(b.N), "store-calls/page")
+				b.ReportMetric(float64(trips)/float64(b.N), "redis-trips/page")
+			})
+		}
+	}
+}
+
+// seedQuestions creates n questions with three tags each
+func seedQuestions(tb testing.TB, st store.Store, n int) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		_, err := st.CreateQuestion(context.Background(), models.QuestionCreateRequest{
+			Title:   fmt.Sprintf("Benchmark question %d", i),
+			Content: "How do I load the tags and counters of a whole page at once?",
+			TagNames: []string{
+				fmt.Sprintf("tag-%d", i%20),
+				fmt.Sprintf("tag-%d", (i+7)%20),
+				fmt.Sprintf("tag-%d", (i+13)%20),
+			},
+		}, nil)
+		if err != nil {
+			tb.Fatalf("seeding questions: %v", err)
+		}
+	}
+}
+
+// perRowList replays the access pattern the list endpoint used before
+// batching: two counter lookups with their own store fallback and one tag
+// query per row
+func perRowList(ctx context.Context, st store.Store, rdb *redis.Client, limit int) error {
+	params := store.ListQuestionsParams{Sort: "created_at", Order: "desc", Limit: limit}
+	if _, err := st.CountQuestions(ctx, params); err != nil {
+		return err
+	}
+	questions, err := st.ListQuestions(ctx, params)
+	if err != nil {
+		return err
+	}
+	for _, q := range questions {
+		for _, countType := range []string{"views", "likes"} {
+			key := countKey(q.ID, countType)
+			if _, err := rdb.Get(ctx, key).Int(); err == nil {
+				continue
+			}
+			views, likes, err := st.GetCounts(ctx, q.ID)
+			if err != nil {
+				return err
+			}
+			count := views
+			if countType == "likes" {
+				count = likes
+			}
+			rdb.Set(ctx, key, count, countTTL)
+		}
+		if _, err := st.GetQuestionTags(ctx, q.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countingStore counts the store calls made while listing questions, each of
+// which is one query against MySQL
+type countingStore struct {
+	store.Store
+	calls atomic.Int64
+}
+
+func (s *countingStore) ListQuestions(ctx context.Context, params store.ListQuestionsParams) ([]models.Question, error) {
+	s.calls.Add(1)
+	return s.Store.ListQuestions(ctx, params)
+}
+
+func (s *countingStore) CountQuestions(ctx context.Context, params store.ListQuestionsParams) (int, error) {
+	s.calls.Add(1)
+	return s.Store.CountQuestions(ctx, params)
+}
+
+func (s *countingStore) GetQuestionTags(ctx context.Context, questionID int64) ([]models.Tag, error) {
+	s.calls.Add(1)
+	return s.Store.GetQuestionTags(ctx, questionID)
+}
+
+func (s *countingStore) GetTagsForQuestions(ctx context.Context, questionIDs []int64) (map[int64][]models.Tag, error) {
+	s.calls.Add(1)
+	return s.Store.GetTagsForQuestions(ctx, questionIDs)
+}
+
+func (s *countingStore) GetCounts(ctx context.Context, questionID int64) (int, int, error) {
+	s.calls.Add(1)
+	return s.Store.GetCounts(ctx, questionID)
+}
+
+func (s *countingStore) GetCountsForQuestions(ctx context.Context, questionIDs []int64) (map[int64]store.QuestionCounts, error) {
+	s.calls.Add(1)
+	return s.Store.GetCountsForQuestions(ctx, questionIDs)
+}
+
+func (s *countingStore) ResolveTags(ctx context.Context, names []string) ([]string, error) {
+	s.calls.Add(1)
+	return s.Store.ResolveTags(ctx, names)
+}
+
+// countingHook counts Redis round trips; a pipeline counts as one
+type countingHook struct {
+	count atomic.Int64
+}
+
+func (h *countingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *countingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.count.Add(1)
+		return next(ctx, cmd)
+	}
+}
+
+func (h *countingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.count.Add(1)
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,514 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// listResponse is the body of GET /questions
+type listResponse struct {
+	Questions []struct {
+		ID       int64  `json:"id"`
+		Title    string `json:"title"`
+		AuthorID *int64 `json:"author_id"`
+	} `json:"questions"`
+	QuestionTags map[int64][]models.Tag `json:"question_tags"`
+	Pagination   struct {
+		Total      int     `json:"total"`
+		Page       int     `json:"page"`
+		Limit      int     `json:"limit"`
+		TotalPages int     `json:"total_pages"`
+		HasMore    bool    `json:"has_more"`
+		NextCursor *string `json:"next_cursor"`
+	} `json:"pagination"`
+	Error string `json:"error"`
+}
+
+// ids returns the IDs of the listed questions in order
+func (r listResponse) ids() []int64 {
+	ids := make([]int64, len(r.Questions))
+	for i, q := range r.Questions {
+		ids[i] = q.ID
+	}
+	return ids
+}
+
+func TestGetQuestionsFilters(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	goroutines := s.createQuestion("Goroutine leaks", "go", "concurrency")
+	pipelines := s.createQuestion("Redis pipelines in Go", "go", "redis")
+	eviction := s.createQuestion("Redis eviction policies", "redis")
+	asyncio := s.createQuestion("Python asyncio", "python", "concurrency")
+
+	if _, err := s.store.CreateAnswer(ctx, models.Answer{QuestionID: pipelines, Content: "Use Pipelined"}); err != nil {
+		t.Fatal(err)
+	}
+	accepted, err := s.store.CreateAnswer(ctx, models.Answer{QuestionID: eviction, Content: "Use allkeys-lru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.store.SetAcceptedAnswer(ctx, eviction, &accepted); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{"no filter", "", []int64{goroutines, pipelines, eviction, asyncio}},
+		{"one tag", "tag=go", []int64{goroutines, pipelines}},
+		{"tag spelling is normalized", "tag=Go", []int64{goroutines, pipelines}},
+		{"all tags", "tags=go&tags=redis", []int64{pipelines}},
+		{"any tag", "tags=go&tags=redis&match=any", []int64{goroutines, pipelines, eviction}},
+		{"excluded tag", "tag=concurrency&-tag=python", []int64{goroutines}},
+		{"unknown tag", "tag=rust", nil},
+		{"unanswered", "status=unanswered", []int64{goroutines, asyncio}},
+		{"answered", "status=answered", []int64{pipelines, eviction}},
+		{"accepted", "status=accepted", []int64{eviction}},
+		{"unknown status is ignored", "status=closed", []int64{goroutines, pipelines, eviction, asyncio}},
+		{"search", "search=redis", []int64{pipelines, eviction}},
+		{"search with tag", "search=redis&tag=go", []int64{pipelines}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp listResponse
+			if code := s.do(http.MethodGet, "/questions?"+tt.query, nil, "", &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", code, http.StatusOK, resp.Error)
+			}
+
+			got := resp.ids()
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			want := append([]int64{}, tt.want...)
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("questions = %v, want %v", got, want)
+			}
+			if resp.Pagination.Total != len(want) {
+				t.Errorf("total = %d, want %d", resp.Pagination.Total, len(want))
+			}
+		})
+	}
+
+	t.Run("tags are returned per question", func(t *testing.T) {
+		var resp listResponse
+		s.do(http.MethodGet, "/questions?tag=redis&-tag=go", nil, "", &resp)
+		tags := resp.QuestionTags[eviction]
+		if len(tags) != 1 || tags[0].Name != "redis" {
+			t.Errorf("tags of question %d = %v, want [redis]", eviction, tags)
+		}
+	})
+
+	t.Run("too many tags", func(t *testing.T) {
+		query := url.Values{}
+		for i := 0; i <= maxTagFilters; i++ {
+			query.Add("tag", fmt.Sprintf("tag-%d", i))
+		}
+		var resp listResponse
+		if code := s.do(http.MethodGet, "/questions?"+query.Encode(), nil, "", &resp); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestGetQuestionsPagination(t *testing.T) {
+	s := newTestServer(t)
+
+	const n = 25
+	var newestFirst []int64
+	odd := make(map[int64]bool)
+	for i := 0; i < n; i++ {
+		tag := "even"
+		if i%2 == 1 {
+			tag = "odd"
+		}
+		id := s.createQuestion(fmt.Sprintf("Question %d", i), tag)
+		newestFirst = append([]int64{id}, newestFirst...)
+		odd[id] = tag == "odd"
+	}
+
+	pages := []struct {
+		name       string
+		query      string
+		want       []int64
+		page       int
+		limit      int
+		totalPages int
+	}{
+		{"first page", "limit=10", newestFirst[:10], 1, 10, 3},
+		{"last page", "page=3&limit=10", newestFirst[20:], 3, 10, 3},
+		{"past the end", "page=4&limit=10", nil, 4, 10, 3},
+		{"invalid page", "page=0&limit=10", newestFirst[:10], 1, 10, 3},
+		{"limit above maximum", "limit=500", newestFirst[:10], 1, 10, 3},
+		{"oldest first", "limit=5&order=asc", []int64{newestFirst[24], newestFirst[23], newestFirst[22], newestFirst[21], newestFirst[20]}, 1, 5, 5},
+	}
+	for _, tt := range pages {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp listResponse
+			if code := s.do(http.MethodGet, "/questions?"+tt.query, nil, "", &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", code, http.StatusOK, resp.Error)
+			}
+			if got := resp.ids(); len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("questions = %v, want %v", got, tt.want)
+			}
+			p := resp.Pagination
+			if p.Total != n || p.Page != tt.page || p.Limit != tt.limit || p.TotalPages != tt.totalPages {
+				t.Errorf("pagination = %+v, want total %d, page %d, limit %d, total pages %d",
+					p, n, tt.page, tt.limit, tt.totalPages)
+			}
+		})
+	}
+
+	t.Run("cursor walks every question once", func(t *testing.T) {
+		var got []int64
+		path := "/questions?limit=10&cursor="
+		for pages := 0; ; pages++ {
+			if pages > n {
+				t.Fatal("cursor did not reach the end")
+			}
+			var resp listResponse
+			if code := s.do(http.MethodGet, path, nil, "", &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", code, http.StatusOK, resp.Error)
+			}
+			got = append(got, resp.ids()...)
+			if !resp.Pagination.HasMore {
+				if resp.Pagination.NextCursor != nil {
+					t.Errorf("last page has next cursor %q", *resp.Pagination.NextCursor)
+				}
+				break
+			}
+			path = "/questions?limit=10&cursor=" + url.QueryEscape(*resp.Pagination.NextCursor)
+		}
+		if !reflect.DeepEqual(got, newestFirst) {
+			t.Errorf("questions = %v, want %v", got, newestFirst)
+		}
+	})
+
+	t.Run("cursor keeps its filter", func(t *testing.T) {
+		var first listResponse
+		s.do(http.MethodGet, "/questions?limit=5&tag=odd&cursor=", nil, "", &first)
+		cursor := url.QueryEscape(*first.Pagination.NextCursor)
+
+		var next listResponse
+		if code := s.do(http.MethodGet, "/questions?limit=5&tag=odd&cursor="+cursor, nil, "", &next); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", code, http.StatusOK, next.Error)
+		}
+		if len(next.Questions) != 5 {
+			t.Errorf("second page has %d questions, want 5", len(next.Questions))
+		}
+		for _, id := range append(first.ids(), next.ids()...) {
+			if !odd[id] {
+				t.Errorf("question %d is not tagged odd", id)
+			}
+		}
+
+		var other listResponse
+		if code := s.do(http.MethodGet, "/questions?limit=5&tag=even&cursor="+cursor, nil, "", &other); code != http.StatusBadRequest {
+			t.Errorf("cursor for another filter: status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		var resp listResponse
+		if code := s.do(http.MethodGet, "/questions?cursor=bogus", nil, "", &resp); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestGetQuestionsStoreError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	st := &failingListStore{Store: store.NewMemoryStore()}
+	h := NewHandler(st, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	engine := gin.New()
+	engine.GET("/questions", h.GetQuestions)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/questions", nil))
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response %q: %v", w.Body.String(), err)
+	}
+	// The store's error is logged, not shown to clients
+	if w.Code != http.StatusInternalServerError || body["error"] != "Failed to retrieve questions" {
+		t.Errorf("got %d %v, want 500 with a fixed message", w.Code, body)
+	}
+}
+
+// failingListStore fails to list questions with an error naming its internals
+type failingListStore struct {
+	store.Store
+}
+
+func (s *failingListStore) ListQuestions(ctx context.Context, params store.ListQuestionsParams) ([]models.Question, error) {
+	return nil, errors.New("dial tcp 10.0.0.5:3306: connection refused")
+}
+
+func TestCreateQuestion(t *testing.T) {
+	s := newTestServer(t)
+	userID, token := s.signIn("alice")
+
+	tests := []struct {
+		name     string
+		body     interface{}
+		token    string
+		status   int
+		tags     []string
+		authorID *int64
+	}{
+		{
+			name:   "anonymous",
+			body:   jsonBody{"title": "How do I close a channel?", "content": "Twice?", "tags": []string{"go"}},
+			status: http.StatusCreated,
+			tags:   []string{"go"},
+		},
+		{
+			name:     "signed in records the author",
+			body:     jsonBody{"title": "How do I close a channel?", "content": "Twice?"},
+			token:    token,
+			status:   http.StatusCreated,
+			tags:     []string{},
+			authorID: &userID,
+		},
+		{
+			name:   "tags are normalized and deduplicated",
+			body:   jsonBody{"title": "Tags", "content": "Spelling", "tags": []string{"Go Lang", "go-lang", "C#"}},
+			status: http.StatusCreated,
+			tags:   []string{"c#", "go-lang"},
+		},
+		{
+			name:   "missing title",
+			body:   jsonBody{"content": "No title"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing content",
+			body:   jsonBody{"title": "No content"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid tag",
+			body:   jsonBody{"title": "Bad tag", "content": "Content", "tags": []string{"no spaces?"}},
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp struct {
+				ID    int64  `json:"id"`
+				Error string `json:"error"`
+			}
+			if code := s.do(http.MethodPost, "/questions", tt.body, tt.token, &resp); code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", code, tt.status, resp.Error)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			ctx := context.Background()
+			question, err := s.store.GetQuestion(ctx, resp.ID)
+			if err != nil {
+				t.Fatalf("created question not stored: %v", err)
+			}
+			if !reflect.DeepEqual(question.AuthorID, tt.authorID) {
+				t.Errorf("author = %v, want %v", question.AuthorID, tt.authorID)
+			}
+			tags, err := s.store.GetQuestionTags(ctx, resp.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, tag := range tags {
+				names = append(names, tag.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.tags) {
+				t.Errorf("tags = %v, want %v", names, tt.tags)
+			}
+		})
+	}
+
+	t.Run("listed after creation", func(t *testing.T) {
+		// Creating a question drops cached list pages
+		var before listResponse
+		s.do(http.MethodGet, "/questions", nil, "", &before)
+		s.do(http.MethodPost, "/questions", jsonBody{"title": "Fresh", "content": "Content"}, "", nil)
+
+		var after listResponse
+		s.do(http.MethodGet, "/questions", nil, "", &after)
+		if after.Pagination.Total != before.Pagination.Total+1 || after.Questions[0].Title != "Fresh" {
+			t.Errorf("new question not listed first: %+v", after.Questions)
+		}
+	})
+}
+
+func TestAddComment(t *testing.T) {
+	s := newTestServer(t)
+	userID, token := s.signIn("alice")
+	questionID := s.createQuestion("Commented question")
+
+	tests := []struct {
+		name   string
+		path   string
+		body   interface{}
+		token  string
+		status int
+	}{
+		{"anonymous", fmt.Sprintf("/questions/%d/comments", questionID), jsonBody{"content": "First"}, "", http.StatusCreated},
+		{"signed in", fmt.Sprintf("/questions/%d/comments", questionID), jsonBody{"content": "Second"}, token, http.StatusCreated},
+		{"missing content", fmt.Sprintf("/questions/%d/comments", questionID), jsonBody{}, "", http.StatusBadRequest},
+		{"unknown question", "/questions/999/comments", jsonBody{"content": "Lost"}, "", http.StatusNotFound},
+		{"invalid question ID", "/questions/abc/comments", jsonBody{"content": "Lost"}, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp struct {
+				Error string `json:"error"`
+			}
+			if code := s.do(http.MethodPost, tt.path, tt.body, tt.token, &resp); code != tt.status {
+				t.Errorf("status = %d, want %d: %s", code, tt.status, resp.Error)
+			}
+		})
+	}
+
+	comments, err := s.store.ListComments(context.Background(), questionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("stored %d comments, want 2", len(comments))
+	}
+	// Comments are listed newest first
+	if comments[0].Content != "Second" || comments[0].AuthorID == nil || *comments[0].AuthorID != userID {
+		t.Errorf("signed-in comment = %+v, want author %d", comments[0], userID)
+	}
+	if comments[1].Content != "First" || comments[1].AuthorID != nil {
+		t.Errorf("anonymous comment = %+v", comments[1])
+	}
+
+	// The comment shows in the question detail, whose cache it invalidated
+	var detail struct {
+		Comments []models.Comment `json:"comments"`
+	}
+	s.do(http.MethodGet, fmt.Sprintf("/questions/%d", questionID), nil, "", &detail)
+	s.do(http.MethodPost, fmt.Sprintf("/questions/%d/comments", questionID), jsonBody{"content": "Third"}, "", nil)
+	s.do(http.MethodGet, fmt.Sprintf("/questions/%d", questionID), nil, "", &detail)
+	if len(detail.Comments) != 3 {
+		t.Errorf("question detail has %d comments, want 3", len(detail.Comments))
+	}
+}
+
+func TestLikeQuestion(t *testing.T) {
+	s := newTestServer(t)
+	_, alice := s.signIn("alice")
+	_, bob := s.signIn("bob")
+	questionID := s.createQuestion("Liked question")
+	path := fmt.Sprintf("/questions/%d/like", questionID)
+
+	// Each step toggles one liker's like; anonymous likers are keyed by IP
+	steps := []struct {
+		name      string
+		token     string
+		liked     bool
+		likeCount int
+	}{
+		{"anonymous likes", "", true, 1},
+		{"alice likes", alice, true, 2},
+		{"bob likes", bob, true, 3},
+		{"alice unlikes", alice, false, 2},
+		{"anonymous unlikes", "", false, 1},
+		{"alice likes again", alice, true, 2},
+	}
+	for _, step := range steps {
+		var resp struct {
+			Liked     bool   `json:"liked"`
+			LikeCount int    `json:"like_count"`
+			Error     string `json:"error"`
+		}
+		if code := s.do(http.MethodPost, path, nil, step.token, &resp); code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d: %s", step.name, code, http.StatusOK, resp.Error)
+		}
+		if resp.Liked != step.liked || resp.LikeCount != step.likeCount {
+			t.Errorf("%s: liked = %v with %d likes, want %v with %d", step.name, resp.Liked, resp.LikeCount, step.liked, step.likeCount)
+		}
+	}
+
+	// The question detail reads the like counter the toggle updated
+	var detail struct {
+		Likes int `json:"likes"`
+	}
+	s.do(http.MethodGet, fmt.Sprintf("/questions/%d", questionID), nil, "", &detail)
+	if detail.Likes != 2 {
+		t.Errorf("question detail has %d likes, want 2", detail.Likes)
+	}
+
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{"/questions/999/like", http.StatusNotFound},
+		{"/questions/abc/like", http.StatusBadRequest},
+	} {
+		if code := s.do(http.MethodPost, tt.path, nil, "", nil); code != tt.status {
+			t.Errorf("POST %s: status = %d, want %d", tt.path, code, tt.status)
+		}
+	}
+}
+
+func TestQuestionPermissions(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, alice := s.signIn("alice")
+	_, bob := s.signIn("bob")
+	_, mod := s.signInModerator("mod")
+
+	// Questions from deleted accounts have no author; only moderators may change them
+	tests := []struct {
+		name   string
+		author *int64
+		token  string
+		status int
+	}{
+		{"anonymous", &aliceID, "", http.StatusUnauthorized},
+		{"another user", &aliceID, bob, http.StatusForbidden},
+		{"author", &aliceID, alice, http.StatusOK},
+		{"moderator", &aliceID, mod, http.StatusOK},
+		{"user on an author-less question", nil, alice, http.StatusForbidden},
+		{"moderator on an author-less question", nil, mod, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questionID, err := s.store.CreateQuestion(context.Background(), models.QuestionCreateRequest{
+				Title: "Guarded question", Content: "Who may change this?",
+			}, tt.author)
+			if err != nil {
+				t.Fatalf("creating question: %v", err)
+			}
+			path := fmt.Sprintf("/questions/%d", questionID)
+
+			if code := s.do(http.MethodPatch, path, jsonBody{"title": "Edited question"}, tt.token, nil); code != tt.status {
+				t.Errorf("edit: status = %d, want %d", code, tt.status)
+			}
+			if code := s.do(http.MethodDelete, path, nil, tt.token, nil); code != tt.status {
+				t.Errorf("delete: status = %d, want %d", code, tt.status)
+			}
+		})
+	}
+}
+
+// jsonBody is shorthand for JSON request bodies
+type jsonBody map[string]interface{}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/diff"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// ListRevisions handles listing the edit history of a question, oldest first
+func (h *Handler) ListRevisions(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	exists, err := h.questions.QuestionExists(ctx, questionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check question existence"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+
+	revisions, err := h.revisions.ListRevisions(ctx, questionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// RevisionDiff handles showing how a revision changed the question compared
+// to the revision before it. Revision 1 is diffed against an empty question.
+func (h *Handler) RevisionDiff(c *gin.Context) {
+	questionID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	current, ok := h.findRevision(c, questionID, rev)
+	if !ok {
+		return
+	}
+
+	var previous models.QuestionRevision
+	var previousRev *int
+	if rev > 1 {
+		var err error
+		previous, err = h.revisions.GetRevision(ctx, questionID, rev-1)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve previous revision"})
+			return
+		}
+		previousRev = &previous.Revision
+	}
+
+	added, removed := diffTags(previous.Tags, current.Tags)
+
+	c.JSON(http.StatusOK, gin.H{
+		"question_id":       questionID,
+		"revision":          current.Revision,
+		"previous_revision": previousRev,
+		"editor_id":         current.EditorID,
+		"rollback_of":       current.RollbackOf,
+		"created_at":        current.CreatedAt,
+		"title":             diff.Lines(previous.Title, current.Title),
+		"content":           diff.Lines(previous.Content, current.Content),
+		"tags": gin.H{
+			"added":   added,
+			"removed": removed,
+		},
+	})
+}
+
+// RollbackRevision handles restoring a question to an earlier revision. The
+// rollback is itself recorded as a new revision, naming who performed it and
+// the revision it restored, so it can be undone. Moderators may roll back any
+// question, e.g. to revert vandalism by its author.
+func (h *Handler) RollbackRevision(c *gin.Context) {
+	questionID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+
+	if !h.authorizeQuestionAuthor(c, questionID, "Only the author or a moderator can roll back this question") {
+		return
+	}
+
+	target, ok := h.findRevision(c, questionID, rev)
+	if !ok {
+		return
+	}
+
+	previousTags := h.questionTagNames(c.Request.Context(), questionID)
+	tags := append([]string{}, target.Tags...)
+	update := store.QuestionUpdate{
+		Title:      &target.Title,
+		Content:    &target.Content,
+		TagNames:   &tags,
+		EditorID:   currentUser(c),
+		RollbackOf: &rev,
+	}
+	if err := h.questions.UpdateQuestion(c.Request.Context(), questionID, update); errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	} else if err != nil {
+		h.log(c.Request.Context()).Error("rolling back question failed", "question_id", questionID, "revision", rev, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back question"})
+		return
+	}
+
+	h.invalidateQuestion(c.Request.Context(), questionID)
+	h.refreshTags(c.Request.Context(), append(previousTags, tags...))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Question rolled back successfully",
+		"rolled_back_to": rev,
+	})
+}
+
+// findRevision loads a revision of a question, writing a 404 when it does not exist
+func (h *Handler) findRevision(c *gin.Context, questionID int64, rev int) (models.QuestionRevision, bool) {
+	revision, err := h.revisions.GetRevision(c.Request.Context(), questionID, rev)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return revision, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve revision"})
+		return revision, false
+	}
+	return revision, true
+}
+
+// parseRevisionPath parses the :id and :rev URL parameters, writing a 400 on failure
+func parseRevisionPath(c *gin.Context) (int64, int, bool) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return 0, 0, false
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || rev < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+		return 0, 0, false
+	}
+	return questionID, rev, true
+}
+
+// diffTags returns the tags present only in next and only in prev
+func diffTags(prev, next []string) (added, removed []string) {
+	inPrev := make(map[string]bool, len(prev))
+	for _, tag := range prev {
+		inPrev[tag] = true
+	}
+	inNext := make(map[string]bool, len(next))
+	for _, tag := range next {
+		inNext[tag] = true
+	}
+
+	added, removed = []string{}, []string{}
+	for _, tag := range next {
+		if !inPrev[tag] {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range prev {
+		if !inNext[tag] {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
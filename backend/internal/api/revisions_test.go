@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/questions/backend/internal/diff"
+)
+
+func TestRollbackRevision(t *testing.T) {
+	s := newTestServer(t)
+	_, alice := s.signIn("alice")
+	_, bob := s.signIn("bob")
+	modID, mod := s.signInModerator("mod")
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	s.do(http.MethodPost, "/questions", jsonBody{"title": "Closing channels", "content": "When is it safe?"}, alice, &created)
+	question := fmt.Sprintf("/questions/%d", created.ID)
+
+	// The author vandalizes their own question
+	if code := s.do(http.MethodPatch, question, jsonBody{"title": "spam spam spam"}, alice, nil); code != http.StatusOK {
+		t.Fatalf("edit: status = %d, want %d", code, http.StatusOK)
+	}
+
+	var change struct {
+		Title []diff.Line `json:"title"`
+	}
+	s.do(http.MethodGet, question+"/revisions/2/diff", nil, "", &change)
+	wantChange := []diff.Line{{Op: diff.OpDelete, Text: "Closing channels"}, {Op: diff.OpInsert, Text: "spam spam spam"}}
+	if fmt.Sprint(change.Title) != fmt.Sprint(wantChange) {
+		t.Errorf("title diff of revision 2 = %v, want %v", change.Title, wantChange)
+	}
+
+	rollback := question + "/revisions/1/rollback"
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"another user", bob, http.StatusForbidden},
+		{"moderator", mod, http.StatusOK},
+	} {
+		if code := s.do(http.MethodPost, rollback, nil, tt.token, nil); code != tt.status {
+			t.Errorf("rollback by %s: status = %d, want %d", tt.name, code, tt.status)
+		}
+	}
+
+	var detail struct {
+		Question struct {
+			Title string `json:"title"`
+		} `json:"question"`
+	}
+	s.do(http.MethodGet, question, nil, "", &detail)
+	if detail.Question.Title != "Closing channels" {
+		t.Errorf("title after rollback = %q, want the original", detail.Question.Title)
+	}
+
+	// The rollback is a new revision naming who performed it and what it restored
+	var history struct {
+		Revisions []struct {
+			Revision   int    `json:"revision"`
+			Title      string `json:"title"`
+			EditorID   *int64 `json:"editor_id"`
+			RollbackOf *int   `json:"rollback_of"`
+		} `json:"revisions"`
+	}
+	s.do(http.MethodGet, question+"/revisions", nil, "", &history)
+	if len(history.Revisions) != 3 {
+		t.Fatalf("%d revisions, want 3", len(history.Revisions))
+	}
+	if edit := history.Revisions[1]; edit.RollbackOf != nil {
+		t.Errorf("edit revision has rollback_of %d", *edit.RollbackOf)
+	}
+	last := history.Revisions[2]
+	if last.Revision != 3 || last.Title != "Closing channels" || last.EditorID == nil || *last.EditorID != modID ||
+		last.RollbackOf == nil || *last.RollbackOf != 1 {
+		t.Errorf("rollback revision = %+v, want revision 3 by %d restoring revision 1", last, modID)
+	}
+
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{question + "/revisions/9/rollback", http.StatusNotFound},
+		{question + "/revisions/0/rollback", http.StatusBadRequest},
+		{"/questions/999/revisions/1/rollback", http.StatusNotFound},
+	} {
+		if code := s.do(http.MethodPost, tt.path, nil, mod, nil); code != tt.status {
+			t.Errorf("POST %s: status = %d, want %d", tt.path, code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/models"
+)
+
+// Default and maximum number of days covered by a stats request
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 366
+)
+
+// GetQuestionStats handles returning a question's daily views, unique visitors
+// and likes to its author. from and to are inclusive UTC days (YYYY-MM-DD) and
+// default to the last 30 days; days without activity are filled with zeros.
+func (h *Handler) GetQuestionStats(c *gin.Context) {
+	questionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(models.StatsDateLayout, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date like 2024-01-31"})
+			return
+		}
+	}
+	from := to.AddDate(0, 0, 1-defaultStatsDays)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(models.StatsDateLayout, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date like 2024-01-01"})
+			return
+		}
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxStatsDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stats cover at most 366 days per request"})
+		return
+	}
+
+	if !h.authorizeQuestionAuthor(c, questionID, "Only the author or a moderator can view question stats") {
+		return
+	}
+
+	stats, err := h.stats.ListDailyStats(c.Request.Context(), questionID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question stats"})
+		return
+	}
+
+	// Turn the days with activity into a continuous series
+	byDate := make(map[string]models.QuestionDayStats, len(stats))
+	for _, stat := range stats {
+		byDate[stat.Date] = stat
+	}
+	var series []models.QuestionDayStats
+	var totalHits, totalVisitors, totalLikes int
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.StatsDateLayout)
+		stat, ok := byDate[date]
+		if !ok {
+			stat = models.QuestionDayStats{Date: date}
+		}
+		totalHits += stat.Hits
+		totalVisitors += stat.UniqueVisitors
+		totalLikes += stat.Likes
+		series = append(series, stat)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"question_id": questionID,
+		"from":        from.Format(models.StatsDateLayout),
+		"to":          to.Format(models.StatsDateLayout),
+		"days":        series,
+		"totals": gin.H{
+			"hits":            totalHits,
+			"unique_visitors": totalVisitors,
+			"likes":           totalLikes,
+		},
+	})
+}
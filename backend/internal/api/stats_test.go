@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+func TestGetQuestionStats(t *testing.T) {
+	s := newTestServer(t)
+	aliceID, alice := s.signIn("alice")
+	_, bob := s.signIn("bob")
+	ctx := context.Background()
+
+	questionID, err := s.store.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Popular question", Content: "Who reads this?"}, &aliceID)
+	if err != nil {
+		t.Fatalf("creating question: %v", err)
+	}
+	day := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	for _, stat := range []struct {
+		day  time.Time
+		hits int
+		ips  int
+	}{{day.AddDate(0, 0, -1), 7, 2}, {day.AddDate(0, 0, 1), 3, 3}} {
+		if err := s.store.SaveViewStats(ctx, stat.day, map[int64]store.DailyViews{questionID: {Hits: stat.hits, UniqueVisitors: stat.ips}}); err != nil {
+			t.Fatalf("saving view stats: %v", err)
+		}
+	}
+	path := fmt.Sprintf("/questions/%d/stats?from=2024-03-01&to=2024-03-03", questionID)
+
+	if code := s.do(http.MethodGet, path, nil, bob, nil); code != http.StatusForbidden {
+		t.Errorf("stats for another user: status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	var resp struct {
+		Days   []models.QuestionDayStats `json:"days"`
+		Totals map[string]int            `json:"totals"`
+	}
+	if code := s.do(http.MethodGet, path, nil, alice, &resp); code != http.StatusOK {
+		t.Fatalf("stats: status = %d, want %d", code, http.StatusOK)
+	}
+	wantDays := []models.QuestionDayStats{
+		{Date: "2024-03-01", Hits: 7, UniqueVisitors: 2},
+		{Date: "2024-03-02"},
+		{Date: "2024-03-03", Hits: 3, UniqueVisitors: 3},
+	}
+	if !reflect.DeepEqual(resp.Days, wantDays) {
+		t.Errorf("days = %+v, want %+v", resp.Days, wantDays)
+	}
+	// Unique visitors add up to what view_count counted for the range
+	wantTotals := map[string]int{"hits": 10, "unique_visitors": 5, "likes": 0}
+	if !reflect.DeepEqual(resp.Totals, wantTotals) {
+		t.Errorf("totals = %v, want %v", resp.Totals, wantTotals)
+	}
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys of the tag autocomplete index. Every tag is a member of both
+// sets: tagNamesKey has score 0 so members sort lexically for prefix range
+// queries, and tagPopularityKey scores each tag by its question count.
+const (
+	tagNamesKey      = "tags:autocomplete"
+	tagPopularityKey = "tags:popularity"
+)
+
+// autocompleteCandidates bounds how many prefix matches Redis ranks by
+// popularity. Prefixes matching more tags are ranked by the store, so the
+// most used tags are suggested however many tags match.
+const autocompleteCandidates = 1000
+
+// errTooManyCandidates reports that a prefix matches more than autocompleteCandidates tags
+var errTooManyCandidates = errors.New("too many tags match the prefix")
+
+// ListTags handles listing the tag directory with usage counts
+func (h *Handler) ListTags(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	sort := c.DefaultQuery("sort", store.TagSortPopular)
+	validSorts := map[string]bool{
+		store.TagSortPopular: true, store.TagSortName: true, store.TagSortNewest: true,
+	}
+	if !validSorts[sort] {
+		sort = store.TagSortPopular
+	}
+
+	params := store.ListTagsParams{
+		Prefix: strings.ToLower(strings.TrimSpace(c.Query("prefix"))),
+		Sort:   sort,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	ctx := c.Request.Context()
+
+	total, err := h.tags.CountTags(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count tags"})
+		return
+	}
+
+	tags, err := h.tags.ListTags(ctx, params)
+	if err != nil {
+		h.log(ctx).Error("listing tags failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	for i := range tags {
+		tags[i].Description = truncateContent(tags[i].Description, 200)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": tags,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + limit - 1) / limit,
+		},
+	})
+}
+
+// AutocompleteTags handles suggesting existing tags for a name prefix, most used first
+func (h *Handler) AutocompleteTags(c *gin.Context) {
+	prefix := strings.ToLower(strings.TrimSpace(c.Query("q")))
+	if prefix == "" {
+		c.JSON(http.StatusOK, gin.H{"tags": []gin.H{}})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 20 {
+		limit = 10
+	}
+
+	ctx := c.Request.Context()
+
+	if h.redisAvailable() {
+		suggestions, err := h.autocompleteFromRedis(ctx, prefix, limit)
+		if err == nil {
+			c.JSON(http.StatusOK, gin.H{"tags": suggestions})
+			return
+		}
+		if !errors.Is(err, errTooManyCandidates) {
+			h.log(ctx).Warn("autocompleting tags from Redis failed; using the store", "error", err)
+		}
+	}
+
+	tags, err := h.tags.ListTags(ctx, store.ListTagsParams{Prefix: prefix, Sort: store.TagSortPopular, Limit: limit})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	suggestions := make([]gin.H, len(tags))
+	for i, tag := range tags {
+		suggestions[i] = gin.H{"name": tag.Name, "question_count": tag.QuestionCount}
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": suggestions})
+}
+
+// GetTag handles showing a tag with its wiki excerpt and top questions
+func (h *Handler) GetTag(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	name, err := h.canonicalTag(ctx, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve tag"})
+		return
+	}
+
+	tag, err := h.tags.GetTag(ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tag"})
+		return
+	}
+
+	questions, err := h.questions.ListQuestions(ctx, store.ListQuestionsParams{
+		Tags:  []string{tag.Name},
+		Sort:  "like_count",
+		Order: "desc",
+		Limit: 5,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
+		return
+	}
+
+	synonyms, err := h.tags.ListTagSynonyms(ctx, tag.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve synonyms"})
+		return
+	}
+	aliases := make([]string, len(synonyms))
+	for i, synonym := range synonyms {
+		aliases[i] = synonym.Alias
+	}
+
+	topQuestions := make([]gin.H, len(questions))
+	for i, q := range questions {
+		topQuestions[i] = gin.H{
+			"id":           q.ID,
+			"title":        q.Title,
+			"like_count":   q.LikeCount,
+			"answer_count": q.AnswerCount,
+			"is_answered":  q.IsAnswered(),
+			"created_at":   q.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tag": gin.H{
+			"id":             tag.ID,
+			"name":           tag.Name,
+			"question_count": tag.QuestionCount,
+			"created_at":     tag.CreatedAt,
+			"synonyms":       aliases,
+		},
+		"excerpt":       truncateContent(tag.Description, 200),
+		"top_questions": topQuestions,
+	})
+}
+
+// UpdateTag handles editing a tag's wiki description; the router only lets
+// moderators reach it
+func (h *Handler) UpdateTag(c *gin.Context) {
+	var req struct {
+		Description string `json:"description" binding:"max=5000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	name, err := h.canonicalTag(ctx, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve tag"})
+		return
+	}
+
+	err = h.tags.UpdateTagDescription(ctx, name, req.Description)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag updated successfully"})
+}
+
+// RebuildTagIndex loads every tag into the Redis autocomplete index. It is a
+// no-op without Redis or while Redis is down.
+func (h *Handler) RebuildTagIndex(ctx context.Context) error {
+	if !h.redisAvailable() {
+		return nil
+	}
+
+	pipe := h.redis.TxPipeline()
+	pipe.Del(ctx, tagNamesKey, tagPopularityKey)
+	params := store.ListTagsParams{Sort: store.TagSortName, Limit: 500}
+	for {
+		tags, err := h.tags.ListTags(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			pipe.ZAdd(ctx, tagNamesKey, redis.Z{Member: tag.Name})
+			pipe.ZAdd(ctx, tagPopularityKey, redis.Z{Member: tag.Name, Score: float64(tag.QuestionCount)})
+		}
+		if len(tags) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// refreshTags updates the autocomplete index entries of the named tags after
+// their question counts may have changed, even if ctx is canceled
+func (h *Handler) refreshTags(ctx context.Context, names []string) {
+	if !h.redisAvailable() || len(names) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	// Names may be aliases that resolve to their canonical tag
+	if resolved, err := h.tags.ResolveTags(ctx, names); err == nil {
+		names = resolved
+	}
+
+	pipe := h.redis.Pipeline()
+	for _, name := range names {
+		tag, err := h.tags.GetTag(ctx, name)
+		if errors.Is(err, store.ErrNotFound) {
+			pipe.ZRem(ctx, tagNamesKey, name)
+			pipe.ZRem(ctx, tagPopularityKey, name)
+			continue
+		} else if err != nil {
+			h.log(ctx).Error("refreshing tag failed", "tag", name, "error", err)
+			continue
+		}
+		pipe.ZAdd(ctx, tagNamesKey, redis.Z{Member: tag.Name})
+		pipe.ZAdd(ctx, tagPopularityKey, redis.Z{Member: tag.Name, Score: float64(tag.QuestionCount)})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.log(ctx).Error("refreshing tag autocomplete index failed", "error", err)
+	}
+}
+
+// canonicalTag normalizes a tag name from a URL and maps a synonym to its canonical tag
+func (h *Handler) canonicalTag(ctx context.Context, name string) (string, error) {
+	if normalized, err := models.NormalizeTagName(name); err == nil {
+		name = normalized
+	}
+	resolved, err := h.tags.ResolveTags(ctx, []string{name})
+	if err != nil {
+		return "", err
+	}
+	return resolved[0], nil
+}
+
+// questionTagNames returns the names of a question's tags, or nil if they cannot be read
+func (h *Handler) questionTagNames(ctx context.Context, questionID int64) []string {
+	tags, err := h.questions.GetQuestionTags(ctx, questionID)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// autocompleteFromRedis finds every tag starting with prefix in the lexical
+// set and ranks them by their popularity score. It returns
+// errTooManyCandidates when more than autocompleteCandidates tags match.
+func (h *Handler) autocompleteFromRedis(ctx context.Context, prefix string, limit int) ([]gin.H, error) {
+	from, to := "["+prefix, "["+prefix+"\xff"
+	matches, err := h.redis.ZLexCount(ctx, tagNamesKey, from, to).Result()
+	if err != nil || matches == 0 {
+		return []gin.H{}, err
+	}
+	if matches > autocompleteCandidates {
+		return nil, errTooManyCandidates
+	}
+
+	names, err := h.redis.ZRangeByLex(ctx, tagNamesKey, &redis.ZRangeBy{Min: from, Max: to}).Result()
+	if err != nil || len(names) == 0 {
+		return []gin.H{}, err
+	}
+
+	scores, err := h.redis.ZMScore(ctx, tagPopularityKey, names...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	if len(order) > limit {
+		order = order[:limit]
+	}
+
+	suggestions := make([]gin.H, len(order))
+	for i, idx := range order {
+		suggestions[i] = gin.H{"name": names[idx], "question_count": int(scores[idx])}
+	}
+	return suggestions, nil
+}
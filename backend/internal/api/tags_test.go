@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAutocompleteTags(t *testing.T) {
+	ctx := context.Background()
+	st := &tagListingStore{Store: store.NewMemoryStore()}
+
+	// ask stores a question with tags directly
+	ask := func(tags ...string) {
+		t.Helper()
+		if _, err := st.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Tagged", Content: "Tagged question", TagNames: tags}, nil); err != nil {
+			t.Fatalf("creating question: %v", err)
+		}
+	}
+
+	// 150 tags start with go- and 1200 with lib-. The most used ones sort
+	// last by name, past the first 100 matches.
+	var goTags, libTags []string
+	for i := 0; i < 150; i++ {
+		goTags = append(goTags, fmt.Sprintf("go-%03d", i))
+	}
+	for i := 0; i < 1200; i++ {
+		libTags = append(libTags, fmt.Sprintf("lib-%04d", i))
+	}
+	ask(goTags...)
+	ask(libTags...)
+	for i := 0; i < 3; i++ {
+		ask("go-149", "lib-1199")
+	}
+	ask("go-120", "lib-1150")
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	h := NewHandler(st, nil, rdb, cache.NewRedis(rdb), slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	if err := h.RebuildTagIndex(ctx); err != nil {
+		t.Fatalf("RebuildTagIndex: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/tags/autocomplete", h.AutocompleteTags)
+
+	tests := []struct {
+		query     string
+		want      []string
+		fromStore bool
+	}{
+		{"go", []string{"go-149", "go-120", "go-000"}, false},
+		{"go-14", []string{"go-149", "go-140", "go-141"}, false},
+		// Too many matches to rank in Redis
+		{"lib", []string{"lib-1199", "lib-1150", "lib-0000"}, true},
+		{"rust", []string{}, false},
+	}
+	for _, tt := range tests {
+		st.listed.Store(0)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tags/autocomplete?limit=3&q="+tt.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("q=%s: status = %d: %s", tt.query, rec.Code, rec.Body.String())
+		}
+
+		var resp struct {
+			Tags []struct {
+				Name string `json:"name"`
+			} `json:"tags"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("q=%s: decoding %q: %v", tt.query, rec.Body.String(), err)
+		}
+		names := []string{}
+		for _, tag := range resp.Tags {
+			names = append(names, tag.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("q=%s suggests %v, want %v", tt.query, names, tt.want)
+		}
+		if fromStore := st.listed.Load() > 0; fromStore != tt.fromStore {
+			t.Errorf("q=%s: ranked by the store = %v, want %v", tt.query, fromStore, tt.fromStore)
+		}
+	}
+}
+
+// tagListingStore counts how often tags are listed from the store
+type tagListingStore struct {
+	store.Store
+	listed atomic.Int64
+}
+
+func (s *tagListingStore) ListTags(ctx context.Context, params store.ListTagsParams) ([]models.TagInfo, error) {
+	s.listed.Add(1)
+	return s.Store.ListTags(ctx, params)
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys under which the middleware stores the current user
+const (
+	userIDKey   = "auth.user_id"
+	usernameKey = "auth.username"
+)
+
+// Middleware populates the current user from a "Bearer" Authorization header.
+// Requests without a token pass through anonymously; requests with an invalid
+// token are rejected.
+func Middleware(tokens *TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header"})
+			return
+		}
+
+		claims, err := tokens.Parse(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		userID, _ := claims.UserID()
+		c.Set(userIDKey, userID)
+		c.Set(usernameKey, claims.Username)
+		c.Next()
+	}
+}
+
+// RequireUser rejects requests that are not signed in
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentUserID(c); !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the signed-in user's ID, if any
+func CurrentUserID(c *gin.Context) (int64, bool) {
+	v, ok := c.Get(userIDKey)
+	if !ok {
+		return 0, false
+	}
+	id, ok := v.(int64)
+	return id, ok
+}
+
+// CurrentUsername returns the signed-in user's username, if any
+func CurrentUsername(c *gin.Context) string {
+	return c.GetString(usernameKey)
+}
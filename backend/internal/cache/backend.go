@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores byte values under string keys until they expire
+type Cache interface {
+	// Get returns the values of keys in order, nil for missing ones. A
+	// counter read through Get is its decimal value.
+	Get(ctx context.Context, keys ...string) ([][]byte, error)
+	// Set stores a value for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys of any kind
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Counter holds integer counters and sets of unique members under string keys.
+// A ttl of 0 keeps a key until it is deleted or evicted.
+type Counter interface {
+	// Counts returns the values of counters in order and whether each exists
+	Counts(ctx context.Context, keys ...string) ([]int64, []bool, error)
+	// InitCounts sets the counters that do not exist yet and leaves the others alone
+	InitCounts(ctx context.Context, values map[string]int64, ttl time.Duration) error
+	// SetCount sets a counter
+	SetCount(ctx context.Context, key string, value int64, ttl time.Duration) error
+	// IncrBy adds delta to a counter, starting a missing one at 0, refreshes
+	// its TTL and returns the new value
+	IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// AddUnique adds member to the set under key, refreshes its TTL and
+	// reports whether the member is new. Sets may be approximate.
+	AddUnique(ctx context.Context, key, member string, ttl time.Duration) (bool, error)
+	// Delete removes keys of any kind
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Backend is a Cache and a Counter sharing one key space
+type Backend interface {
+	Cache
+	Counter
+}
@@ -0,0 +1,162 @@
+// Package cache provides key-value backends for cached values and counters,
+// in Redis or in process, and cache-aside reads on top of them with versioned
+// tag invalidation and stampede protection
+package cache
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/questions/backend/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// versionPrefix prefixes the keys holding tag versions
+const versionPrefix = "cache:version:"
+
+// tracer starts the spans of cached reads
+var tracer = otel.Tracer("github.com/questions/backend/internal/cache")
+
+// Stats counts the outcomes of Fetch for one kind of value
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Errors counts backend failures; the value is then loaded uncached
+	Errors int64 `json:"errors"`
+}
+
+// Versioned stores loaded values in a Backend under a key and a set of tags.
+// Every value records the versions its tags had when it was loaded, and
+// bumping a tag's version makes every value stored under it stale at once,
+// without enumerating keys. A load racing with an invalidation therefore
+// stores a value that is already stale rather than one that looks current.
+type Versioned struct {
+	backend Backend
+	logger  *slog.Logger
+	group   singleflight.Group
+
+	mu    sync.Mutex
+	stats map[string]*Stats // kind -> stats
+}
+
+// NewVersioned creates a Versioned storing values and tag versions in
+// backend. Failures are logged to the logger of the context, or else to logger.
+func NewVersioned(backend Backend, logger *slog.Logger) *Versioned {
+	return &Versioned{backend: backend, logger: logging.OrDefault(logger), stats: make(map[string]*Stats)}
+}
+
+// Fetch returns the value cached under key if it is current for every tag.
+// Otherwise it calls load, caches the result for ttl and returns it.
+// Concurrent misses on one key share a single load. kind groups the key in
+// Stats. Load errors are returned as is and never cached.
+func (c *Versioned) Fetch(ctx context.Context, kind, key string, tags []string, ttl time.Duration,
+	load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	tags = append([]string(nil), tags...)
+	sort.Strings(tags)
+
+	keys := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		keys = append(keys, versionPrefix+tag)
+	}
+	keys = append(keys, key)
+
+	ctx, span := tracer.Start(ctx, "cache fetch", trace.WithAttributes(attribute.String("cache.kind", kind)))
+	defer span.End()
+
+	var versions string
+	values, err := c.backend.Get(ctx, keys...)
+	if err != nil {
+		c.record(kind, func(s *Stats) { s.Errors++ })
+	} else {
+		versions = tagVersions(values[:len(tags)])
+		if current, payload, ok := bytes.Cut(values[len(tags)], []byte("\n")); ok && string(current) == versions {
+			c.record(kind, func(s *Stats) { s.Hits++ })
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			return payload, nil
+		}
+	}
+	c.record(kind, func(s *Stats) { s.Misses++ })
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	// The load runs once for every caller waiting on the key, so it must not
+	// be cut short when the first caller goes away
+	loadCtx := context.WithoutCancel(ctx)
+	value, err, _ := c.group.Do(key, func() (interface{}, error) {
+		payload, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		if versions != "" {
+			var buf bytes.Buffer
+			buf.WriteString(versions)
+			buf.WriteByte('\n')
+			buf.Write(payload)
+			if err := c.backend.Set(loadCtx, key, buf.Bytes(), ttl); err != nil {
+				c.record(kind, func(s *Stats) { s.Errors++ })
+			}
+		}
+		return payload, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]byte), nil
+}
+
+// Invalidate makes every value cached under any of the tags stale
+func (c *Versioned) Invalidate(ctx context.Context, tags ...string) {
+	for _, tag := range tags {
+		if _, err := c.backend.IncrBy(ctx, versionPrefix+tag, 1, 0); err != nil {
+			logging.FromContext(ctx, c.logger).Error("invalidating cache tag failed", "tag", tag, "error", err)
+		}
+	}
+}
+
+// Stats returns a snapshot of the hit and miss counts per kind
+func (c *Versioned) Stats() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]Stats, len(c.stats))
+	for kind, s := range c.stats {
+		snapshot[kind] = *s
+	}
+	return snapshot
+}
+
+// record updates the stats of a kind
+func (c *Versioned) record(kind string, update func(s *Stats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[kind]
+	if !ok {
+		s = &Stats{}
+		c.stats[kind] = s
+	}
+	update(s)
+}
+
+// tagVersions renders the current versions of tags from the values of their
+// version keys; tags that were never invalidated are at version 0. The result
+// is never empty.
+func tagVersions(values [][]byte) string {
+	b := []byte("v")
+	for _, value := range values {
+		b = append(b, ':')
+		if _, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			b = append(b, value...)
+		} else {
+			b = append(b, '0')
+		}
+	}
+	return string(b)
+}
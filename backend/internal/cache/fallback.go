@@ -0,0 +1,350 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/questions/backend/internal/logging"
+)
+
+// DefaultProbeInterval is how often a degraded Fallback checks whether Redis is back
+const DefaultProbeInterval = 5 * time.Second
+
+// maxPendingKeys bounds how many keys a Fallback remembers for replay during
+// one outage; changes to further keys are served locally but not replayed
+const maxPendingKeys = 100000
+
+// Fallback is a Backend that uses Redis while it answers and an in-process
+// Memory while it does not. The first Redis error degrades it; Run then probes
+// Redis and, once it answers again, replays the counter changes made in
+// memory meanwhile before switching back. Cached values set during the outage
+// are not replayed, and the values Redis kept may have missed invalidations,
+// so the functions registered with OnRecover should drop them.
+type Fallback struct {
+	primary *Redis
+	local   *Memory
+	logger  *slog.Logger
+
+	mu        sync.Mutex
+	degraded  bool
+	pending   *changes // changes served locally since Redis failed
+	onRecover []func(ctx context.Context)
+}
+
+// NewFallback creates a Fallback using primary and falling back to local.
+// A nil logger logs to the default logger.
+func NewFallback(primary *Redis, local *Memory, logger *slog.Logger) *Fallback {
+	return &Fallback{primary: primary, local: local, logger: logging.OrDefault(logger), pending: newChanges()}
+}
+
+// OnRecover registers fn to run every time Redis is back and the local
+// changes have been replayed
+func (f *Fallback) OnRecover(fn func(ctx context.Context)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onRecover = append(f.onRecover, fn)
+}
+
+// Degraded reports whether the Fallback is serving from memory
+func (f *Fallback) Degraded() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.degraded
+}
+
+// Degrade switches to memory until Redis is back, e.g. when Redis could not
+// be reached at startup
+func (f *Fallback) Degrade(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.degrade(err)
+}
+
+// Run probes Redis every interval while degraded until ctx is done
+func (f *Fallback) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if f.Degraded() {
+				if err := f.recover(ctx); err != nil {
+					f.logger.Error("replaying changes to Redis failed", "error", err)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recover replays the local changes to Redis and switches back to it if Redis
+// answers. Changes keep being served locally until none are left to replay.
+func (f *Fallback) recover(ctx context.Context) error {
+	if f.primary.Ping(ctx) != nil {
+		// Still down; that was logged when Redis went down
+		return nil
+	}
+
+	for {
+		f.mu.Lock()
+		batch := f.pending
+		if batch.len() == 0 {
+			f.degraded = false
+			f.pending = newChanges()
+			callbacks := f.onRecover
+			f.mu.Unlock()
+
+			f.local.Clear()
+			for _, fn := range callbacks {
+				fn(ctx)
+			}
+			f.logger.Info("Redis is back; switched from the in-process fallback")
+			return nil
+		}
+		f.pending = newChanges()
+		f.mu.Unlock()
+
+		if err := f.primary.apply(ctx, batch); err != nil {
+			// The transaction did not apply, so the batch can be retried as a whole
+			f.mu.Lock()
+			batch.merge(f.pending)
+			f.pending = batch
+			f.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// Get implements Cache
+func (f *Fallback) Get(ctx context.Context, keys ...string) ([][]byte, error) {
+	if !f.Degraded() {
+		values, err := f.primary.Get(ctx, keys...)
+		if !f.failed(ctx, err) {
+			return values, err
+		}
+	}
+	return f.local.Get(ctx, keys...)
+}
+
+// Set implements Cache
+func (f *Fallback) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if !f.Degraded() {
+		err := f.primary.Set(ctx, key, value, ttl)
+		if !f.failed(ctx, err) {
+			return err
+		}
+	}
+	return f.local.Set(ctx, key, value, ttl)
+}
+
+// Delete implements Cache and Counter
+func (f *Fallback) Delete(ctx context.Context, keys ...string) error {
+	if !f.Degraded() {
+		err := f.primary.Delete(ctx, keys...)
+		if !f.failed(ctx, err) {
+			return err
+		}
+	}
+	return f.locally(func(c *changes) error {
+		for _, key := range keys {
+			c.delete(key)
+		}
+		return f.local.Delete(ctx, keys...)
+	})
+}
+
+// Counts implements Counter
+func (f *Fallback) Counts(ctx context.Context, keys ...string) ([]int64, []bool, error) {
+	if !f.Degraded() {
+		counts, found, err := f.primary.Counts(ctx, keys...)
+		if !f.failed(ctx, err) {
+			return counts, found, err
+		}
+	}
+	return f.local.Counts(ctx, keys...)
+}
+
+// InitCounts implements Counter. Initial values come from the store, so they
+// are not replayed.
+func (f *Fallback) InitCounts(ctx context.Context, values map[string]int64, ttl time.Duration) error {
+	if !f.Degraded() {
+		err := f.primary.InitCounts(ctx, values, ttl)
+		if !f.failed(ctx, err) {
+			return err
+		}
+	}
+	return f.local.InitCounts(ctx, values, ttl)
+}
+
+// SetCount implements Counter
+func (f *Fallback) SetCount(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	if !f.Degraded() {
+		err := f.primary.SetCount(ctx, key, value, ttl)
+		if !f.failed(ctx, err) {
+			return err
+		}
+	}
+	return f.locally(func(c *changes) error {
+		c.set(key, value, ttl)
+		return f.local.SetCount(ctx, key, value, ttl)
+	})
+}
+
+// IncrBy implements Counter
+func (f *Fallback) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if !f.Degraded() {
+		n, err := f.primary.IncrBy(ctx, key, delta, ttl)
+		if !f.failed(ctx, err) {
+			return n, err
+		}
+	}
+	var n int64
+	err := f.locally(func(c *changes) error {
+		c.incr(key, delta, ttl)
+		var err error
+		n, err = f.local.IncrBy(ctx, key, delta, ttl)
+		return err
+	})
+	return n, err
+}
+
+// AddUnique implements Counter
+func (f *Fallback) AddUnique(ctx context.Context, key, member string, ttl time.Duration) (bool, error) {
+	if !f.Degraded() {
+		added, err := f.primary.AddUnique(ctx, key, member, ttl)
+		if !f.failed(ctx, err) {
+			return added, err
+		}
+	}
+	var added bool
+	err := f.locally(func(c *changes) error {
+		c.addMember(key, member, ttl)
+		var err error
+		added, err = f.local.AddUnique(ctx, key, member, ttl)
+		return err
+	})
+	return added, err
+}
+
+// failed reports whether a Redis call failed in a way that calls for the
+// fallback, degrading the Fallback if so. Calls cut short by their own
+// context say nothing about Redis.
+func (f *Fallback) failed(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	f.Degrade(err)
+	return true
+}
+
+// degrade switches to memory; f.mu must be held
+func (f *Fallback) degrade(err error) {
+	if !f.degraded {
+		f.logger.Warn("Redis unavailable; using the in-process fallback", "error", err)
+		f.degraded = true
+	}
+}
+
+// locally applies a change to memory and records it for replay as one step,
+// so a concurrent recovery cannot switch back between the two
+func (f *Fallback) locally(apply func(c *changes) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pending.len() >= maxPendingKeys {
+		return apply(newChanges())
+	}
+	return apply(f.pending)
+}
+
+// changes collects counter changes to replay, folded per key
+type changes struct {
+	deletes map[string]bool
+	sets    map[string]countChange
+	incrs   map[string]countChange
+	members map[string]*memberChange
+}
+
+// countChange is a counter value or delta and the TTL it was written with
+type countChange struct {
+	value int64
+	ttl   time.Duration
+}
+
+// memberChange is a set of members added under a key
+type memberChange struct {
+	set map[string]struct{}
+	ttl time.Duration
+}
+
+// newChanges creates an empty set of changes
+func newChanges() *changes {
+	return &changes{
+		deletes: make(map[string]bool),
+		sets:    make(map[string]countChange),
+		incrs:   make(map[string]countChange),
+		members: make(map[string]*memberChange),
+	}
+}
+
+// len returns how many keys have changes
+func (c *changes) len() int {
+	return len(c.deletes) + len(c.sets) + len(c.incrs) + len(c.members)
+}
+
+// delete records deleting key, which supersedes its earlier changes.
+// Deletes are replayed first, so later changes to the key still apply.
+func (c *changes) delete(key string) {
+	delete(c.sets, key)
+	delete(c.incrs, key)
+	delete(c.members, key)
+	c.deletes[key] = true
+}
+
+// set records setting a counter, which supersedes its earlier increments
+func (c *changes) set(key string, value int64, ttl time.Duration) {
+	delete(c.incrs, key)
+	c.sets[key] = countChange{value: value, ttl: ttl}
+}
+
+// incr records adding delta to a counter
+func (c *changes) incr(key string, delta int64, ttl time.Duration) {
+	if set, ok := c.sets[key]; ok {
+		c.sets[key] = countChange{value: set.value + delta, ttl: ttl}
+		return
+	}
+	incr := c.incrs[key]
+	c.incrs[key] = countChange{value: incr.value + delta, ttl: ttl}
+}
+
+// addMember records adding a member to a set
+func (c *changes) addMember(key, member string, ttl time.Duration) {
+	m, ok := c.members[key]
+	if !ok {
+		m = &memberChange{set: make(map[string]struct{})}
+		c.members[key] = m
+	}
+	m.set[member] = struct{}{}
+	m.ttl = ttl
+}
+
+// merge folds later changes into c
+func (c *changes) merge(later *changes) {
+	for key := range later.deletes {
+		c.delete(key)
+	}
+	for key, set := range later.sets {
+		c.set(key, set.value, set.ttl)
+	}
+	for key, incr := range later.incrs {
+		c.incr(key, incr.value, incr.ttl)
+	}
+	for key, m := range later.members {
+		for member := range m.set {
+			c.addMember(key, member, m.ttl)
+		}
+	}
+}
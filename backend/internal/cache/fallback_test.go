@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestFallback creates a Fallback over an in-process Redis
+func newTestFallback(t *testing.T) (*Fallback, *miniredis.Miniredis, *Memory) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	local := NewMemory(DefaultMemoryEntries)
+	return NewFallback(NewRedis(rdb), local, slog.New(slog.NewTextHandler(io.Discard, nil))), mr, local
+}
+
+func TestFallbackReplay(t *testing.T) {
+	ctx := context.Background()
+	f, mr, local := newTestFallback(t)
+
+	for key, value := range map[string]int64{"views": 10, "likes": 3, "answers": 1} {
+		if err := f.SetCount(ctx, key, value, time.Hour); err != nil {
+			t.Fatalf("SetCount(%s): %v", key, err)
+		}
+	}
+	if f.Degraded() {
+		t.Fatal("degraded while Redis answers")
+	}
+
+	// Redis goes down: the first failing call degrades the Fallback and is
+	// served from memory, as are the calls after it
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if n, err := f.IncrBy(ctx, "views", 2, time.Hour); err != nil || n != 2 {
+		t.Fatalf("IncrBy(views) = %d, %v; want 2 from an empty local counter", n, err)
+	}
+	if !f.Degraded() {
+		t.Fatal("not degraded after a Redis error")
+	}
+	steps := []func() error{
+		func() error { _, err := f.IncrBy(ctx, "views", 3, time.Hour); return err },
+		func() error { return f.SetCount(ctx, "likes", 7, time.Hour) },
+		func() error { _, err := f.IncrBy(ctx, "likes", 1, time.Hour); return err },
+		func() error { return f.Delete(ctx, "answers") },
+		func() error { _, err := f.IncrBy(ctx, "evicted", 4, time.Hour); return err },
+		func() error { _, err := f.AddUnique(ctx, "viewers", "alice", time.Hour); return err },
+		func() error { _, err := f.AddUnique(ctx, "viewers", "bob", time.Hour); return err },
+		// Initial values come from the store and are not replayed
+		func() error { return f.InitCounts(ctx, map[string]int64{"initialized": 9}, time.Hour) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d while degraded: %v", i, err)
+		}
+	}
+
+	// Probing a Redis that is still down keeps serving from memory
+	if err := f.recover(ctx); err != nil || !f.Degraded() {
+		t.Fatalf("recover while down: %v, degraded = %v; want nil and degraded", err, f.Degraded())
+	}
+
+	recovered := 0
+	f.OnRecover(func(context.Context) { recovered++ })
+	mr.SetError("")
+	if err := f.recover(ctx); err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if f.Degraded() || recovered != 1 || local.Len() != 0 {
+		t.Errorf("after recovery: degraded = %v, %d callback run(s), %d local key(s); want false, 1 and 0",
+			f.Degraded(), recovered, local.Len())
+	}
+
+	counts, found, err := f.Counts(ctx, "views", "likes", "answers", "evicted", "initialized")
+	if err != nil {
+		t.Fatalf("Counts: %v", err)
+	}
+	want := []struct {
+		key   string
+		count int64
+		found bool
+	}{
+		{"views", 15, true},
+		{"likes", 8, true},
+		{"answers", 0, false},
+		// Increments only touch counters Redis still has
+		{"evicted", 0, false},
+		{"initialized", 0, false},
+	}
+	for i, w := range want {
+		if counts[i] != w.count || found[i] != w.found {
+			t.Errorf("%s = %d (found %v), want %d (found %v)", w.key, counts[i], found[i], w.count, w.found)
+		}
+	}
+	if n, err := mr.PfCount("viewers"); err != nil || n != 2 {
+		t.Errorf("viewers = %d, %v; want 2 unique members", n, err)
+	}
+	if ttl := mr.TTL("viewers"); ttl != time.Hour {
+		t.Errorf("viewers TTL = %v, want %v", ttl, time.Hour)
+	}
+}
+
+func TestFallbackIgnoresCanceledCalls(t *testing.T) {
+	f, mr, _ := newTestFallback(t)
+	mr.SetError("ERR down")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Get(ctx, "key"); err == nil {
+		t.Error("Get with a canceled context succeeded")
+	}
+	if f.Degraded() {
+		t.Error("degraded by a call cut short by its own context")
+	}
+}
+
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  func(c *changes)
+		deletes []string
+		sets    map[string]int64
+		incrs   map[string]int64
+		members map[string]int
+	}{
+		{
+			name:   "increments add up",
+			record: func(c *changes) { c.incr("a", 2, 0); c.incr("a", -1, 0) },
+			incrs:  map[string]int64{"a": 1},
+		},
+		{
+			name:   "increments after a set fold into it",
+			record: func(c *changes) { c.incr("a", 2, 0); c.set("a", 10, 0); c.incr("a", 3, 0) },
+			sets:   map[string]int64{"a": 13},
+		},
+		{
+			name: "a delete supersedes earlier changes",
+			record: func(c *changes) {
+				c.set("a", 1, 0)
+				c.incr("b", 1, 0)
+				c.addMember("c", "x", 0)
+				c.delete("a")
+				c.delete("b")
+				c.delete("c")
+			},
+			deletes: []string{"a", "b", "c"},
+		},
+		{
+			name:    "changes after a delete still apply",
+			record:  func(c *changes) { c.delete("a"); c.incr("a", 1, 0); c.addMember("a", "x", 0) },
+			deletes: []string{"a"},
+			incrs:   map[string]int64{"a": 1},
+			members: map[string]int{"a": 1},
+		},
+		{
+			name:    "members are kept once",
+			record:  func(c *changes) { c.addMember("a", "x", 0); c.addMember("a", "x", 0); c.addMember("a", "y", 0) },
+			members: map[string]int{"a": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChanges()
+			tt.record(c)
+			check(t, c, tt.deletes, tt.sets, tt.incrs, tt.members)
+		})
+	}
+
+	// A batch that failed to replay is retried with the changes made meanwhile
+	t.Run("merge", func(t *testing.T) {
+		batch, later := newChanges(), newChanges()
+		batch.incr("a", 1, 0)
+		batch.set("b", 5, 0)
+		batch.addMember("c", "x", 0)
+		later.incr("a", 2, 0)
+		later.incr("b", 1, 0)
+		later.addMember("c", "y", 0)
+		later.delete("d")
+		batch.merge(later)
+		check(t, batch, []string{"d"}, map[string]int64{"b": 6}, map[string]int64{"a": 3}, map[string]int{"c": 2})
+	})
+}
+
+// check compares the changes recorded in c with the expected deletes, set
+// values, increments and member counts
+func check(t *testing.T, c *changes, deletes []string, sets, incrs map[string]int64, members map[string]int) {
+	t.Helper()
+	if len(c.deletes) != len(deletes) {
+		t.Errorf("deletes = %v, want %v", c.deletes, deletes)
+	}
+	for _, key := range deletes {
+		if !c.deletes[key] {
+			t.Errorf("delete of %s not recorded", key)
+		}
+	}
+	if len(c.sets) != len(sets) || len(c.incrs) != len(incrs) || len(c.members) != len(members) {
+		t.Errorf("recorded %d set(s), %d increment(s) and %d member key(s), want %d, %d and %d",
+			len(c.sets), len(c.incrs), len(c.members), len(sets), len(incrs), len(members))
+	}
+	for key, value := range sets {
+		if c.sets[key].value != value {
+			t.Errorf("set %s = %d, want %d", key, c.sets[key].value, value)
+		}
+	}
+	for key, delta := range incrs {
+		if c.incrs[key].value != delta {
+			t.Errorf("increment %s = %d, want %d", key, c.incrs[key].value, delta)
+		}
+	}
+	for key, n := range members {
+		if m := c.members[key]; m == nil || len(m.set) != n {
+			t.Errorf("members of %s = %v, want %d", key, m, n)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMemoryEntries is how many keys a Memory holds unless configured otherwise
+const DefaultMemoryEntries = 50000
+
+// Memory is a Backend holding keys in process, evicting the least recently
+// used key once it holds maxEntries. Each server instance has its own, so
+// counters and cached values are not shared between instances. Unique members
+// are kept exactly.
+type Memory struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // key -> element holding a *memoryEntry
+	order   *list.List               // most recently used first
+}
+
+// memoryEntry is one key of a Memory. It holds either a value, which counters
+// keep in decimal, or a set of members.
+type memoryEntry struct {
+	key     string
+	value   []byte
+	members map[string]struct{}
+	expires time.Time // zero never expires
+}
+
+// NewMemory creates a Memory holding at most maxEntries keys. A Memory
+// without room stores nothing.
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{maxEntries: maxEntries, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Len returns how many keys the Memory holds, including expired ones not yet dropped
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Clear drops every key
+func (m *Memory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*list.Element)
+	m.order.Init()
+}
+
+// Get implements Cache
+func (m *Memory) Get(ctx context.Context, keys ...string) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if e := m.get(key); e != nil && e.members == nil {
+			values[i] = e.value
+		}
+	}
+	return values, nil
+}
+
+// Set implements Cache
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(&memoryEntry{key: key, value: value, expires: expiry(ttl)})
+	return nil
+}
+
+// Delete implements Cache and Counter
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		m.remove(key)
+	}
+	return nil
+}
+
+// Counts implements Counter
+func (m *Memory) Counts(ctx context.Context, keys ...string) ([]int64, []bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make([]int64, len(keys))
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		counts[i], found[i] = m.count(key)
+	}
+	return counts, found, nil
+}
+
+// InitCounts implements Counter
+func (m *Memory) InitCounts(ctx context.Context, values map[string]int64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, value := range values {
+		if m.get(key) == nil {
+			m.put(&memoryEntry{key: key, value: formatCount(value), expires: expiry(ttl)})
+		}
+	}
+	return nil
+}
+
+// SetCount implements Counter
+func (m *Memory) SetCount(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(&memoryEntry{key: key, value: formatCount(value), expires: expiry(ttl)})
+	return nil
+}
+
+// IncrBy implements Counter
+func (m *Memory) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n, _ := m.count(key)
+	n += delta
+	expires := expiry(ttl)
+	if e := m.get(key); e != nil && ttl <= 0 {
+		// Like INCRBY without EXPIRE, keep the counter's TTL
+		expires = e.expires
+	}
+	m.put(&memoryEntry{key: key, value: formatCount(n), expires: expires})
+	return n, nil
+}
+
+// AddUnique implements Counter
+func (m *Memory) AddUnique(ctx context.Context, key, member string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	members := make(map[string]struct{})
+	if e := m.get(key); e != nil && e.members != nil {
+		members = e.members
+	}
+	_, seen := members[member]
+	members[member] = struct{}{}
+	m.put(&memoryEntry{key: key, members: members, expires: expiry(ttl)})
+	return !seen, nil
+}
+
+// get returns the live entry of key and marks it recently used, dropping it
+// if it expired
+func (m *Memory) get(key string) *memoryEntry {
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	e := elem.Value.(*memoryEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		m.remove(key)
+		return nil
+	}
+	m.order.MoveToFront(elem)
+	return e
+}
+
+// count returns the value of the counter under key and whether it exists
+func (m *Memory) count(key string) (int64, bool) {
+	e := m.get(key)
+	if e == nil || e.members != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(string(e.value), 10, 64)
+	return n, err == nil
+}
+
+// put stores an entry, replacing any entry of its key, and evicts the least
+// recently used entries beyond maxEntries
+func (m *Memory) put(e *memoryEntry) {
+	if elem, ok := m.entries[e.key]; ok {
+		elem.Value = e
+		m.order.MoveToFront(elem)
+	} else {
+		m.entries[e.key] = m.order.PushFront(e)
+	}
+	for m.order.Len() > m.maxEntries {
+		m.remove(m.order.Back().Value.(*memoryEntry).key)
+	}
+}
+
+// remove drops the entry of key
+func (m *Memory) remove(key string) {
+	if elem, ok := m.entries[key]; ok {
+		m.order.Remove(elem)
+		delete(m.entries, key)
+	}
+}
+
+// expiry returns when a key stored now for ttl expires
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// formatCount renders a counter the way Redis stores it
+func formatCount(n int64) []byte {
+	return strconv.AppendInt(nil, n, 10)
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Backend storing keys in Redis, shared by every server instance.
+// Unique members are counted with HyperLogLogs.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis creates a Redis backend using rdb
+func NewRedis(rdb *redis.Client) *Redis {
+	return &Redis{client: rdb}
+}
+
+// Ping checks that Redis answers
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Get implements Cache
+func (r *Redis) Get(ctx context.Context, keys ...string) ([][]byte, error) {
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]byte, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			result[i] = []byte(s)
+		}
+	}
+	return result, nil
+}
+
+// Set implements Cache
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete implements Cache and Counter
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// Counts implements Counter
+func (r *Redis) Counts(ctx context.Context, keys ...string) ([]int64, []bool, error) {
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	counts := make([]int64, len(values))
+	found := make([]bool, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				counts[i], found[i] = n, true
+			}
+		}
+	}
+	return counts, found, nil
+}
+
+// InitCounts implements Counter. SETNX keeps a concurrent INCR from being overwritten.
+func (r *Redis) InitCounts(ctx context.Context, values map[string]int64, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for key, value := range values {
+		pipe.SetNX(ctx, key, value, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// SetCount implements Counter
+func (r *Redis) SetCount(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// IncrBy implements Counter
+func (r *Redis) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, key, delta)
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// AddUnique implements Counter
+func (r *Redis) AddUnique(ctx context.Context, key, member string, ttl time.Duration) (bool, error) {
+	pipe := r.client.TxPipeline()
+	added := pipe.PFAdd(ctx, key, member)
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return added.Val() == 1, nil
+}
+
+// incrExistingScript adds ARGV[1] to the counter KEYS[1] only if it exists and
+// refreshes its TTL of ARGV[2] milliseconds unless that is 0
+var incrExistingScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// apply replays changes made elsewhere in one transaction. Increments only
+// touch counters Redis still has: readers reload a missing counter from the
+// store, which already holds what the increments stand for.
+func (r *Redis) apply(ctx context.Context, c *changes) error {
+	pipe := r.client.TxPipeline()
+	for key := range c.deletes {
+		pipe.Del(ctx, key)
+	}
+	for key, set := range c.sets {
+		pipe.Set(ctx, key, set.value, set.ttl)
+	}
+	for key, incr := range c.incrs {
+		incrExistingScript.Eval(ctx, pipe, []string{key}, incr.value, incr.ttl.Milliseconds())
+	}
+	for key, members := range c.members {
+		list := make([]interface{}, 0, len(members.set))
+		for member := range members.set {
+			list = append(list, member)
+		}
+		pipe.PFAdd(ctx, key, list...)
+		if members.ttl > 0 {
+			pipe.Expire(ctx, key, members.ttl)
+		}
+	}
+	if pipe.Len() == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to replay %d change(s): %w", c.len(), err)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+// Package config loads the server configuration from defaults, an optional
+// YAML file, .env and the environment into typed settings
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/counters"
+	"github.com/questions/backend/internal/ratelimit"
+)
+
+// Config is the complete server configuration. Every setting has a key in
+// the YAML file (section.name) and an environment variable (the env tag).
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Log       LogConfig       `yaml:"log"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Health    HealthConfig    `yaml:"health"`
+	Storage   StorageConfig   `yaml:"storage"`
+	MySQL     MySQLConfig     `yaml:"mysql"`
+	Redis     RedisConfig     `yaml:"redis"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Counters  CountersConfig  `yaml:"counters"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	JWT       JWTConfig       `yaml:"jwt"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port            int           `yaml:"port" env:"PORT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// TrustedProxies lists, comma-separated, the IPs and CIDRs of reverse
+	// proxies whose X-Forwarded-For header names the client. Empty trusts
+	// none, so clients are identified by their own address.
+	TrustedProxies string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+// Proxies returns the trusted proxies, none when unset. The setting was
+// validated on load.
+func (c *ServerConfig) Proxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// LogConfig configures the server's log output
+type LogConfig struct {
+	// Format is text or json
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+// MetricsConfig configures the Prometheus metrics endpoint
+type MetricsConfig struct {
+	// Enabled serves /metrics and records the metrics
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED"`
+}
+
+// TracingConfig configures OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter is none, otlp (OTLP over HTTP) or file (JSON lines)
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// Endpoint is the host:port of the OTLP collector
+	Endpoint string `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	// Insecure sends spans to the collector without TLS
+	Insecure bool   `yaml:"insecure" env:"TRACING_INSECURE"`
+	File     string `yaml:"file" env:"TRACING_FILE"`
+	// ServiceName identifies the server in traces
+	ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	// SamplePercent is the share of traces started here that are recorded;
+	// traces continued from a caller follow the caller's decision
+	SamplePercent int `yaml:"sample_percent" env:"TRACING_SAMPLE_PERCENT"`
+}
+
+// HealthConfig configures the health and readiness endpoints
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency ping of /readyz and /status
+	CheckTimeout time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+	// DrainDelay is how long shutdown keeps serving with /readyz failing, so
+	// load balancers stop routing here before connections are refused
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HEALTH_DRAIN_DELAY"`
+}
+
+// StorageConfig selects the store and its search engine
+type StorageConfig struct {
+	// Backend is mysql or memory
+	Backend string `yaml:"backend" env:"STORAGE"`
+	// SearchEngine is native or index; it defaults to index for the memory store
+	SearchEngine string `yaml:"search_engine" env:"SEARCH_ENGINE"`
+	// AutoMigrate applies pending migrations on startup
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+}
+
+// MySQLConfig configures the MySQL connection pool
+type MySQLConfig struct {
+	Host            string        `yaml:"host" env:"MYSQL_HOST"`
+	Port            int           `yaml:"port" env:"MYSQL_PORT"`
+	User            string        `yaml:"user" env:"MYSQL_USER"`
+	Password        string        `yaml:"password" env:"MYSQL_PASSWORD" secret:"true"`
+	Database        string        `yaml:"database" env:"MYSQL_DATABASE"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"MYSQL_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"MYSQL_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"MYSQL_CONN_MAX_LIFETIME"`
+	DialTimeout     time.Duration `yaml:"dial_timeout" env:"MYSQL_DIAL_TIMEOUT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"MYSQL_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"MYSQL_WRITE_TIMEOUT"`
+}
+
+// RedisConfig configures the Redis connection pool
+type RedisConfig struct {
+	Host         string        `yaml:"host" env:"REDIS_HOST"`
+	Port         int           `yaml:"port" env:"REDIS_PORT"`
+	Password     string        `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB           int           `yaml:"db" env:"REDIS_DB"`
+	PoolSize     int           `yaml:"pool_size" env:"REDIS_POOL_SIZE"`
+	MinIdleConns int           `yaml:"min_idle_conns" env:"REDIS_MIN_IDLE_CONNS"`
+	DialTimeout  time.Duration `yaml:"dial_timeout" env:"REDIS_DIAL_TIMEOUT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"REDIS_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"REDIS_WRITE_TIMEOUT"`
+}
+
+// CacheConfig selects where cached pages and counters live
+type CacheConfig struct {
+	// Backend is redis or memory; it defaults to memory for the memory store
+	Backend       string        `yaml:"backend" env:"CACHE_BACKEND"`
+	MemoryEntries int           `yaml:"memory_entries" env:"CACHE_MEMORY_ENTRIES"`
+	ProbeInterval time.Duration `yaml:"probe_interval" env:"CACHE_PROBE_INTERVAL"`
+}
+
+// CountersConfig configures the background workers writing Redis counters to the store
+type CountersConfig struct {
+	FlushInterval  time.Duration `yaml:"flush_interval" env:"COUNTER_FLUSH_INTERVAL"`
+	RollupInterval time.Duration `yaml:"rollup_interval" env:"STATS_ROLLUP_INTERVAL"`
+}
+
+// RateLimitConfig limits write requests per user, or per client IP for
+// anonymous requests. Limits are written as requests/period, e.g. 5/1m, or off.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Questions limits creating questions
+	Questions string `yaml:"questions" env:"RATE_LIMIT_QUESTIONS"`
+	// Answers limits posting answers
+	Answers string `yaml:"answers" env:"RATE_LIMIT_ANSWERS"`
+	// Comments limits comments on questions and answers together
+	Comments string `yaml:"comments" env:"RATE_LIMIT_COMMENTS"`
+	// Likes limits toggling likes
+	Likes string `yaml:"likes" env:"RATE_LIMIT_LIKES"`
+}
+
+// routes returns the limit setting of every limited route by route name
+func (c *RateLimitConfig) routes() map[string]*string {
+	return map[string]*string{
+		"questions": &c.Questions,
+		"answers":   &c.Answers,
+		"comments":  &c.Comments,
+		"likes":     &c.Likes,
+	}
+}
+
+// Limits returns the limits by route name, none when disabled. The settings
+// were validated on load.
+func (c *RateLimitConfig) Limits() map[string]ratelimit.Limit {
+	limits := make(map[string]ratelimit.Limit)
+	if !c.Enabled {
+		return limits
+	}
+	for route, setting := range c.routes() {
+		limits[route], _ = ratelimit.ParseLimit(*setting)
+	}
+	return limits
+}
+
+// JWTConfig configures the signing of auth tokens
+type JWTConfig struct {
+	// Secret signs tokens; empty uses a random secret, invalidating tokens on restart
+	Secret     string        `yaml:"secret" env:"JWT_SECRET" secret:"true"`
+	Expiration time.Duration `yaml:"expiration" env:"JWT_EXPIRATION"`
+}
+
+// Default returns the configuration used for settings that are not set
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8081,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		Log:     LogConfig{Format: "text", Level: "info"},
+		Metrics: MetricsConfig{Enabled: true},
+		Tracing: TracingConfig{
+			Exporter:      "none",
+			Endpoint:      "localhost:4318",
+			Insecure:      true,
+			File:          "traces.jsonl",
+			ServiceName:   "questions-backend",
+			SamplePercent: 100,
+		},
+		Health:  HealthConfig{CheckTimeout: 2 * time.Second},
+		Storage: StorageConfig{Backend: "mysql"},
+		MySQL: MySQLConfig{
+			Host:            "localhost",
+			Port:            3306,
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			DialTimeout:     5 * time.Second,
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+		},
+		Redis: RedisConfig{
+			Host:         "localhost",
+			Port:         6379,
+			PoolSize:     20,
+			MinIdleConns: 2,
+			DialTimeout:  2 * time.Second,
+			ReadTimeout:  time.Second,
+			WriteTimeout: time.Second,
+		},
+		Cache: CacheConfig{
+			MemoryEntries: cache.DefaultMemoryEntries,
+			ProbeInterval: cache.DefaultProbeInterval,
+		},
+		Counters: CountersConfig{
+			FlushInterval:  counters.DefaultInterval,
+			RollupInterval: counters.DefaultRollupInterval,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:   true,
+			Questions: "5/1m",
+			Answers:   "10/1m",
+			Comments:  "20/1m",
+			Likes:     "30/1m",
+		},
+		JWT: JWTConfig{Expiration: 24 * time.Hour},
+	}
+}
+
+// UsesRedis reports whether the server connects to Redis
+func (c *Config) UsesRedis() bool {
+	return c.Cache.Backend == "redis"
+}
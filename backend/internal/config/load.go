@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in printed configurations
+const redacted = "REDACTED"
+
+// Error lists every invalid setting found while loading
+type Error struct {
+	Problems []string
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Load reads the configuration from, in increasing precedence, the defaults,
+// the YAML file named by CONFIG_FILE and the environment. .env is loaded into
+// the environment first without overriding variables that are already set.
+// The configuration is returned as far as it could be read even when the
+// error lists invalid settings.
+func Load() (*Config, error) {
+	cfg := Default()
+	var problems []string
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		problems = append(problems, fmt.Sprintf(".env: %v", err))
+	}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		problems = append(problems, cfg.loadFile(path)...)
+	}
+	problems = append(problems, cfg.loadEnv()...)
+
+	// Backends left unset follow the store
+	if cfg.Cache.Backend == "" {
+		cfg.Cache.Backend = "redis"
+		if cfg.Storage.Backend == "memory" {
+			cfg.Cache.Backend = "memory"
+		}
+	}
+	if cfg.Storage.SearchEngine == "" {
+		cfg.Storage.SearchEngine = "native"
+		if cfg.Storage.Backend == "memory" {
+			cfg.Storage.SearchEngine = "index"
+		}
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return &cfg, &Error{Problems: problems}
+	}
+	return &cfg, nil
+}
+
+// setting is one leaf of a Config
+type setting struct {
+	path   string // section.key in the YAML file
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// name identifies the setting in messages
+func (s setting) name() string {
+	return fmt.Sprintf("%s (%s)", s.path, s.env)
+}
+
+// set parses raw into the setting
+func (s setting) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch s.value.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("want a duration such as 30s, got %q", raw)
+		}
+		s.value.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("want an integer, got %q", raw)
+		}
+		s.value.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("want true or false, got %q", raw)
+		}
+		s.value.SetBool(b)
+	default:
+		s.value.SetString(raw)
+	}
+	return nil
+}
+
+// format renders the setting's value the way set parses it
+func (s setting) format() string {
+	if s.secret && s.value.String() != "" {
+		return redacted
+	}
+	return fmt.Sprint(s.value.Interface())
+}
+
+// settings lists the leaves of c in declaration order
+func (c *Config) settings() []setting {
+	var list []setting
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		sectionKey := root.Type().Field(i).Tag.Get("yaml")
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			list = append(list, setting{
+				path:   sectionKey + "." + field.Tag.Get("yaml"),
+				env:    field.Tag.Get("env"),
+				secret: field.Tag.Get("secret") == "true",
+				value:  section.Field(j),
+			})
+		}
+	}
+	return list
+}
+
+// loadFile applies the settings of a YAML file made of sections of scalars
+func (c *Config) loadFile(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("CONFIG_FILE: %v", err)}
+	}
+	var sections map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &sections); err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+
+	byPath := make(map[string]setting)
+	for _, s := range c.settings() {
+		byPath[s.path] = s
+	}
+
+	var problems []string
+	for _, sectionKey := range sortedKeys(sections) {
+		values := sections[sectionKey]
+		for _, key := range sortedKeys(values) {
+			value := values[key]
+			s, ok := byPath[sectionKey+"."+key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown setting %s.%s", path, sectionKey, key))
+				continue
+			}
+			raw := ""
+			if value != nil {
+				raw = fmt.Sprint(value)
+			}
+			if err := s.set(raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", s.name(), err))
+			}
+		}
+	}
+	return problems
+}
+
+// sortedKeys returns the keys of m in order, so problems are listed stably
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// loadEnv applies the environment variables that are set
+func (c *Config) loadEnv() []string {
+	var problems []string
+	for _, s := range c.settings() {
+		raw, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := s.set(raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", s.name(), err))
+		}
+	}
+	return problems
+}
+
+// YAML renders the configuration as a YAML file Load accepts, with secrets
+// redacted and each setting's environment variable as a comment
+func (c *Config) YAML() ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	var section *yaml.Node
+	sectionKey := ""
+	for _, s := range c.settings() {
+		key, name, _ := strings.Cut(s.path, ".")
+		if key != sectionKey {
+			sectionKey = key
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, section)
+		}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: s.format(), LineComment: s.env}
+		if _, ok := s.value.Interface().(string); ok {
+			// Keep strings such as "" and "123" strings
+			value.Style = yaml.DoubleQuotedStyle
+		}
+		section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate runs the test in an empty directory, so no .env is read, with
+// every configuration variable unset. It returns the directory.
+func isolate(t *testing.T) string {
+	t.Helper()
+	cfg := Default()
+	for _, s := range cfg.settings() {
+		t.Setenv(s.env, "")
+		os.Unsetenv(s.env)
+	}
+	t.Setenv("CONFIG_FILE", "")
+	os.Unsetenv("CONFIG_FILE")
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+// writeFile writes a file into dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	isolate(t)
+	t.Setenv("STORAGE", "memory")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	want := Default()
+	want.Storage.Backend = "memory"
+	// The memory store defaults to the memory cache and the index search engine
+	want.Cache.Backend = "memory"
+	want.Storage.SearchEngine = "index"
+	if !reflect.DeepEqual(*cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", *cfg, want)
+	}
+}
+
+func TestLoadBackendDefaults(t *testing.T) {
+	isolate(t)
+	t.Setenv("MYSQL_USER", "questions")
+	t.Setenv("MYSQL_DATABASE", "questions_db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if cfg.Cache.Backend != "redis" || cfg.Storage.SearchEngine != "native" {
+		t.Errorf("MySQL store defaults to cache %q and search %q, want redis and native",
+			cfg.Cache.Backend, cfg.Storage.SearchEngine)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := isolate(t)
+
+	// Each source overrides the one before: defaults, YAML, .env, environment
+	t.Setenv("CONFIG_FILE", writeFile(t, dir, "config.yaml", `
+server:
+  port: 9000
+  read_timeout: 5s
+log:
+  level: debug
+  format: json
+storage:
+  backend: memory
+rate_limit:
+  likes: off
+`))
+	writeFile(t, dir, ".env", "PORT=9100\nLOG_LEVEL=warn\n")
+	t.Setenv("LOG_LEVEL", "error")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"default", cfg.Server.WriteTimeout, 30 * time.Second},
+		{"YAML over default", cfg.Server.ReadTimeout, 5 * time.Second},
+		{"YAML string", cfg.Log.Format, "json"},
+		{"YAML off", cfg.RateLimit.Likes, "off"},
+		{".env over YAML", cfg.Server.Port, 9100},
+		{"environment over .env", cfg.Log.Level, "error"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		want []string
+	}{
+		{
+			name: "malformed values",
+			env:  map[string]string{"PORT": "http", "HTTP_READ_TIMEOUT": "5", "METRICS_ENABLED": "yes please"},
+			want: []string{
+				`server.port (PORT): want an integer, got "http"`,
+				`server.read_timeout (HTTP_READ_TIMEOUT): want a duration such as 30s, got "5"`,
+				`metrics.enabled (METRICS_ENABLED): want true or false, got "yes please"`,
+			},
+		},
+		{
+			name: "unknown YAML setting",
+			yaml: "server:\n  prot: 8080\n",
+			want: []string{"unknown setting server.prot"},
+		},
+		{
+			name: "malformed YAML",
+			yaml: "server: [",
+			want: []string{"config.yaml:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := isolate(t)
+			t.Setenv("STORAGE", "memory")
+			if tt.yaml != "" {
+				t.Setenv("CONFIG_FILE", writeFile(t, dir, "config.yaml", tt.yaml))
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			var cfgErr *Error
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("Load() error = %v, want *Error", err)
+			}
+			if cfg == nil {
+				t.Error("Load() returned no configuration along with the error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+
+	t.Run("missing config file", func(t *testing.T) {
+		dir := isolate(t)
+		t.Setenv("STORAGE", "memory")
+		t.Setenv("CONFIG_FILE", filepath.Join(dir, "missing.yaml"))
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "CONFIG_FILE:") {
+			t.Errorf("Load() error = %v, want a CONFIG_FILE problem", err)
+		}
+	})
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	dir := isolate(t)
+
+	cfg := Default()
+	cfg.Storage.Backend = "memory"
+	cfg.Cache.Backend = "memory"
+	cfg.Storage.SearchEngine = "index"
+	cfg.Server.Port = 9000
+	cfg.Tracing.Endpoint = ""
+	cfg.RateLimit.Likes = "off"
+	cfg.JWT.Secret = "hunter2"
+
+	data, err := cfg.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), redacted) {
+		t.Errorf("YAML() does not redact the JWT secret:\n%s", data)
+	}
+	if !strings.Contains(string(data), "# JWT_SECRET") {
+		t.Errorf("YAML() does not name environment variables:\n%s", data)
+	}
+
+	t.Setenv("CONFIG_FILE", writeFile(t, dir, "config.yaml", string(data)))
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("loading rendered YAML: %v", err)
+	}
+	cfg.JWT.Secret = redacted
+	if !reflect.DeepEqual(*loaded, cfg) {
+		t.Errorf("loaded %+v, want %+v", *loaded, cfg)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/ratelimit"
+)
+
+// validator collects the problems of a Config
+type validator struct {
+	settings []setting
+	problems []string
+}
+
+// check records msg against field, a pointer to a setting of the Config,
+// unless ok
+func (v *validator) check(ok bool, field interface{}, msg string) {
+	if ok {
+		return
+	}
+	ptr := reflect.ValueOf(field).Pointer()
+	for _, s := range v.settings {
+		if s.value.Addr().Pointer() == ptr {
+			v.problems = append(v.problems, fmt.Sprintf("%s: %s", s.name(), msg))
+			return
+		}
+	}
+	v.problems = append(v.problems, msg)
+}
+
+// oneOf checks that a string setting has one of the allowed values
+func (v *validator) oneOf(field *string, allowed ...string) {
+	for _, value := range allowed {
+		if *field == value {
+			return
+		}
+	}
+	v.check(false, field, fmt.Sprintf("want one of %v, got %q", allowed, *field))
+}
+
+// positive checks that a duration setting is above zero
+func (v *validator) positive(fields ...interface{}) {
+	for _, field := range fields {
+		d := reflect.ValueOf(field).Elem().Int()
+		v.check(d > 0, field, "must be positive")
+	}
+}
+
+// port checks that a setting is a TCP port
+func (v *validator) port(field *int) {
+	v.check(*field >= 1 && *field <= 65535, field, fmt.Sprintf("want a port between 1 and 65535, got %d", *field))
+}
+
+// required checks that a string setting is not empty
+func (v *validator) required(field *string) {
+	v.check(*field != "", field, "is required")
+}
+
+// validate returns every problem of c
+func (c *Config) validate() []string {
+	v := &validator{settings: c.settings()}
+
+	v.port(&c.Server.Port)
+	v.positive(&c.Server.ReadTimeout, &c.Server.WriteTimeout, &c.Server.IdleTimeout, &c.Server.ShutdownTimeout)
+	for _, proxy := range c.Server.Proxies() {
+		_, _, err := net.ParseCIDR(proxy)
+		v.check(err == nil || net.ParseIP(proxy) != nil, &c.Server.TrustedProxies,
+			fmt.Sprintf("want IP addresses or CIDRs, got %q", proxy))
+	}
+
+	v.oneOf(&c.Log.Format, logging.Formats...)
+	v.oneOf(&c.Log.Level, logging.Levels...)
+
+	v.oneOf(&c.Tracing.Exporter, "none", "otlp", "file")
+	switch c.Tracing.Exporter {
+	case "otlp":
+		v.required(&c.Tracing.Endpoint)
+	case "file":
+		v.required(&c.Tracing.File)
+	}
+	v.required(&c.Tracing.ServiceName)
+	v.check(c.Tracing.SamplePercent >= 0 && c.Tracing.SamplePercent <= 100, &c.Tracing.SamplePercent,
+		fmt.Sprintf("want a percentage between 0 and 100, got %d", c.Tracing.SamplePercent))
+
+	v.positive(&c.Health.CheckTimeout)
+	v.check(c.Health.DrainDelay >= 0 && c.Health.DrainDelay < c.Server.ShutdownTimeout, &c.Health.DrainDelay,
+		fmt.Sprintf("must be between 0 and shutdown_timeout (%s)", c.Server.ShutdownTimeout))
+
+	v.oneOf(&c.Storage.Backend, "mysql", "memory")
+	v.oneOf(&c.Storage.SearchEngine, "native", "index")
+	if c.Storage.Backend == "mysql" {
+		v.required(&c.MySQL.Host)
+		v.port(&c.MySQL.Port)
+		v.required(&c.MySQL.User)
+		v.required(&c.MySQL.Database)
+		v.check(c.MySQL.MaxOpenConns >= 1, &c.MySQL.MaxOpenConns, "must be at least 1")
+		v.check(c.MySQL.MaxIdleConns >= 0 && c.MySQL.MaxIdleConns <= c.MySQL.MaxOpenConns, &c.MySQL.MaxIdleConns,
+			fmt.Sprintf("must be between 0 and max_open_conns (%d)", c.MySQL.MaxOpenConns))
+		v.check(c.MySQL.ConnMaxLifetime >= 0, &c.MySQL.ConnMaxLifetime, "must not be negative")
+		v.positive(&c.MySQL.DialTimeout, &c.MySQL.ReadTimeout, &c.MySQL.WriteTimeout)
+	}
+
+	v.oneOf(&c.Cache.Backend, "redis", "memory")
+	v.check(c.Cache.MemoryEntries >= 0, &c.Cache.MemoryEntries, "must not be negative")
+	v.positive(&c.Cache.ProbeInterval)
+	if c.UsesRedis() {
+		v.required(&c.Redis.Host)
+		v.port(&c.Redis.Port)
+		v.check(c.Redis.DB >= 0, &c.Redis.DB, "must not be negative")
+		v.check(c.Redis.PoolSize >= 1, &c.Redis.PoolSize, "must be at least 1")
+		v.check(c.Redis.MinIdleConns >= 0 && c.Redis.MinIdleConns <= c.Redis.PoolSize, &c.Redis.MinIdleConns,
+			fmt.Sprintf("must be between 0 and pool_size (%d)", c.Redis.PoolSize))
+		v.positive(&c.Redis.DialTimeout, &c.Redis.ReadTimeout, &c.Redis.WriteTimeout)
+		v.positive(&c.Counters.FlushInterval, &c.Counters.RollupInterval)
+	}
+
+	if c.RateLimit.Enabled {
+		for _, setting := range []*string{&c.RateLimit.Questions, &c.RateLimit.Answers, &c.RateLimit.Comments, &c.RateLimit.Likes} {
+			_, err := ratelimit.ParseLimit(*setting)
+			v.check(err == nil, setting, fmt.Sprint(err))
+		}
+	}
+
+	v.positive(&c.JWT.Expiration)
+	return v.problems
+}
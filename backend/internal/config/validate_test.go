@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	// valid returns a configuration that passes validation
+	valid := func() Config {
+		cfg := Default()
+		cfg.Storage.SearchEngine = "native"
+		cfg.Cache.Backend = "redis"
+		cfg.MySQL.User = "questions"
+		cfg.MySQL.Database = "questions_db"
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string // one substring per expected problem
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"port", func(c *Config) { c.Server.Port = 70000 },
+			[]string{"server.port (PORT): want a port between 1 and 65535, got 70000"}},
+		{"timeouts", func(c *Config) { c.Server.ReadTimeout = 0; c.Server.IdleTimeout = -time.Second },
+			[]string{"server.read_timeout (HTTP_READ_TIMEOUT): must be positive", "server.idle_timeout (HTTP_IDLE_TIMEOUT): must be positive"}},
+		{"trusted proxies", func(c *Config) { c.Server.TrustedProxies = "10.0.0.0/8, 192.168.1.7,::1" }, nil},
+		{"invalid trusted proxy", func(c *Config) { c.Server.TrustedProxies = "10.0.0.1,proxy.local" },
+			[]string{`server.trusted_proxies (TRUSTED_PROXIES): want IP addresses or CIDRs, got "proxy.local"`}},
+		{"log level", func(c *Config) { c.Log.Level = "verbose" },
+			[]string{`log.level (LOG_LEVEL): want one of`}},
+		{"tracing exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" },
+			[]string{"tracing.exporter (TRACING_EXPORTER): want one of [none otlp file]"}},
+		{"otlp needs an endpoint", func(c *Config) { c.Tracing.Exporter = "otlp"; c.Tracing.Endpoint = "" },
+			[]string{"tracing.endpoint (TRACING_ENDPOINT): is required"}},
+		{"endpoint unused without otlp", func(c *Config) { c.Tracing.Endpoint = "" }, nil},
+		{"sample percent", func(c *Config) { c.Tracing.SamplePercent = 101 },
+			[]string{"tracing.sample_percent (TRACING_SAMPLE_PERCENT): want a percentage between 0 and 100, got 101"}},
+		{"drain delay", func(c *Config) { c.Health.DrainDelay = c.Server.ShutdownTimeout },
+			[]string{"health.drain_delay (HEALTH_DRAIN_DELAY): must be between 0 and shutdown_timeout (20s)"}},
+		{"storage", func(c *Config) { c.Storage.Backend = "sqlite" },
+			[]string{"storage.backend (STORAGE): want one of [mysql memory]"}},
+		{"mysql credentials", func(c *Config) { c.MySQL.User = ""; c.MySQL.Database = "" },
+			[]string{"mysql.user (MYSQL_USER): is required", "mysql.database (MYSQL_DATABASE): is required"}},
+		{"mysql pool", func(c *Config) { c.MySQL.MaxIdleConns = 30 },
+			[]string{"mysql.max_idle_conns (MYSQL_MAX_IDLE_CONNS): must be between 0 and max_open_conns (25)"}},
+		{"mysql unused by memory store", func(c *Config) { c.Storage.Backend = "memory"; c.MySQL.User = "" }, nil},
+		{"redis pool", func(c *Config) { c.Redis.PoolSize = 0 },
+			[]string{"redis.pool_size (REDIS_POOL_SIZE): must be at least 1", "redis.min_idle_conns (REDIS_MIN_IDLE_CONNS)"}},
+		{"redis unused by memory cache", func(c *Config) { c.Cache.Backend = "memory"; c.Redis.Port = 0 }, nil},
+		{"rate limit", func(c *Config) { c.RateLimit.Likes = "lots" },
+			[]string{"rate_limit.likes (RATE_LIMIT_LIKES):"}},
+		{"rate limit disabled", func(c *Config) { c.RateLimit.Enabled = false; c.RateLimit.Likes = "lots" }, nil},
+		{"jwt expiration", func(c *Config) { c.JWT.Expiration = 0 },
+			[]string{"jwt.expiration (JWT_EXPIRATION): must be positive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			problems := cfg.validate()
+			if len(problems) != len(tt.want) {
+				t.Fatalf("validate() = %q, want %d problems", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package counters buffers question view counts and daily view analytics in
+// Redis and writes them behind to the store in batches
+package counters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/questions/backend/internal/logging"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracer starts a trace per flush and rollup, under which their queries and
+// Redis commands are traced
+var tracer = otel.Tracer("github.com/questions/backend/internal/counters")
+
+// Redis keys used by the write-behind buffer
+const (
+	// DirtyKey is the set of question IDs with views not yet written to the store
+	DirtyKey = "questions:views:dirty"
+	// flushingKey holds the deltas claimed by a flush until the store has them,
+	// so a flush interrupted by a crash is retried on the next run
+	flushingKey = "questions:views:flushing"
+	// lockKey lets one flusher at a time claim deltas across server instances
+	lockKey = "questions:views:flush-lock"
+
+	// pendingPrefix and pendingSuffix surround the question ID in pending keys
+	pendingPrefix = "question:"
+	pendingSuffix = ":views:pending"
+)
+
+// DefaultInterval is how often Run flushes unless configured otherwise
+const DefaultInterval = 10 * time.Second
+
+// batchSize is how many questions a flush claims per store write
+const batchSize = 500
+
+// lockTTL bounds how long a crashed flusher blocks the others. A running flush
+// extends it before each batch, so only a single batch has to finish within it.
+const lockTTL = time.Minute
+
+// PendingKey returns the key holding a question's unflushed view delta
+func PendingKey(questionID int64) string {
+	return pendingPrefix + strconv.FormatInt(questionID, 10) + pendingSuffix
+}
+
+// Tracker records question views in Redis for the Flusher and the Rollup
+type Tracker struct {
+	redis *redis.Client
+}
+
+// NewTracker creates a Tracker writing to rdb
+func NewTracker(rdb *redis.Client) *Tracker {
+	return &Tracker{redis: rdb}
+}
+
+// QueueView adds one view of a question to the write-behind buffer
+func (t *Tracker) QueueView(ctx context.Context, questionID int64) error {
+	pipe := t.redis.TxPipeline()
+	pipe.Incr(ctx, PendingKey(questionID))
+	pipe.SAdd(ctx, DirtyKey, questionID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Purge drops the unflushed views and daily visitor counts of a question
+// deleted at now. Visitor keys outlive their day by the retention window, so
+// the keys of every day in it are deleted, along with the next day's in case
+// another instance's clock is ahead.
+func (t *Tracker) Purge(ctx context.Context, questionID int64, now time.Time) error {
+	keys := []string{PendingKey(questionID)}
+	for i := -1; i <= statsRetentionDays; i++ {
+		keys = append(keys, VisitorsKey(questionID, now.AddDate(0, 0, -i)))
+	}
+
+	pipe := t.redis.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.SRem(ctx, DirtyKey, questionID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// claimScript moves up to ARGV[1] dirty questions' deltas into the flushing
+// hash and returns the whole hash, including deltas left by an earlier flush
+// that failed. Pending keys are built from ARGV[2] and ARGV[3] around the ID.
+var claimScript = redis.NewScript(`
+local ids = redis.call('SPOP', KEYS[1], ARGV[1])
+for _, id in ipairs(ids) do
+	local key = ARGV[2] .. id .. ARGV[3]
+	local delta = redis.call('GET', key)
+	if delta then
+		redis.call('DEL', key)
+		redis.call('HINCRBY', KEYS[2], id, delta)
+	end
+end
+return redis.call('HGETALL', KEYS[2])
+`)
+
+// extendScript resets the flush lock's expiry to ARGV[2] milliseconds and
+// returns 1 only if this flusher still holds it
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// unlockScript releases the flush lock only if this flusher still holds it
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Store persists flushed view deltas
+type Store interface {
+	AddViewCounts(ctx context.Context, deltas map[int64]int) error
+}
+
+// Flusher periodically writes the buffered view deltas to the store. Deltas
+// stay in Redis until the store accepts them, so they survive restarts; a
+// crash between the store write and clearing the claim can count a batch twice.
+type Flusher struct {
+	redis    *redis.Client
+	store    Store
+	interval time.Duration
+	token    string
+	logger   *slog.Logger
+}
+
+// NewFlusher creates a Flusher writing to st every interval. A nil logger logs
+// to the default logger.
+func NewFlusher(rdb *redis.Client, st Store, interval time.Duration, logger *slog.Logger) (*Flusher, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate flush lock token: %w", err)
+	}
+	return &Flusher{redis: rdb, store: st, interval: interval, token: hex.EncodeToString(buf), logger: logging.OrDefault(logger)}, nil
+}
+
+// Run flushes every interval until ctx is done, then flushes once more so
+// views buffered before shutdown reach the store
+func (f *Flusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.flushAndLog(ctx)
+		case <-ctx.Done():
+			// The final flush gets its own deadline since ctx is already done
+			final, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			f.flushAndLog(final)
+			cancel()
+			return
+		}
+	}
+}
+
+// flushAndLog runs Flush and logs failures, and non-empty results at debug level
+func (f *Flusher) flushAndLog(ctx context.Context) {
+	ctx, span := tracer.Start(ctx, "flush view counts")
+	defer span.End()
+
+	n, err := f.Flush(ctx)
+	span.SetAttributes(attribute.Int("questions", n))
+	if err != nil {
+		f.logger.Error("flushing view counts failed", "error", err)
+	} else if n > 0 {
+		f.logger.Debug("flushed view counts", "questions", n)
+	}
+}
+
+// Flush writes every buffered delta to the store in batches and returns how
+// many questions were updated. It does nothing while another flusher holds
+// the lock, and stops with an error if it loses the lock between batches.
+func (f *Flusher) Flush(ctx context.Context) (int, error) {
+	locked, err := f.redis.SetNX(ctx, lockKey, f.token, lockTTL).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to take flush lock: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer unlockScript.Run(context.WithoutCancel(ctx), f.redis, []string{lockKey}, f.token)
+
+	flushed := 0
+	for {
+		// Another flusher taking over an expired lock would apply the
+		// claimed batch a second time
+		held, err := extendScript.Run(ctx, f.redis, []string{lockKey}, f.token, lockTTL.Milliseconds()).Int()
+		if err != nil {
+			return flushed, fmt.Errorf("failed to extend flush lock: %w", err)
+		}
+		if held == 0 {
+			return flushed, errors.New("flush lock expired")
+		}
+
+		deltas, claimed, err := f.claim(ctx)
+		if err != nil {
+			return flushed, err
+		}
+		if claimed == 0 {
+			return flushed, nil
+		}
+		if err := f.store.AddViewCounts(ctx, deltas); err != nil {
+			// The claim stays in the flushing hash and is retried next time
+			return flushed, err
+		}
+		if err := f.redis.Del(ctx, flushingKey).Err(); err != nil {
+			return flushed, fmt.Errorf("failed to clear flushed views: %w", err)
+		}
+		flushed += len(deltas)
+	}
+}
+
+// claim moves the next batch of deltas into the flushing hash and returns the
+// valid ones together with how many entries the hash held
+func (f *Flusher) claim(ctx context.Context) (map[int64]int, int, error) {
+	values, err := claimScript.Run(ctx, f.redis, []string{DirtyKey, flushingKey},
+		batchSize, pendingPrefix, pendingSuffix).StringSlice()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to claim buffered views: %w", err)
+	}
+
+	deltas := make(map[int64]int, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		id, err := strconv.ParseInt(values[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		delta, err := strconv.Atoi(values[i+1])
+		if err != nil || delta == 0 {
+			continue
+		}
+		deltas[id] = delta
+	}
+	return deltas, len(values) / 2, nil
+}
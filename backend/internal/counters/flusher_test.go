@@ -0,0 +1,89 @@
+package counters
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// slowStore records the deltas it is given and runs onWrite after each write
+type slowStore struct {
+	deltas  map[int64]int
+	writes  int
+	onWrite func()
+}
+
+// AddViewCounts implements Store
+func (s *slowStore) AddViewCounts(ctx context.Context, deltas map[int64]int) error {
+	for id, delta := range deltas {
+		s.deltas[id] += delta
+	}
+	s.writes++
+	s.onWrite()
+	return nil
+}
+
+func TestFlushLock(t *testing.T) {
+	ctx := context.Background()
+	queued := 3 * batchSize
+
+	tests := []struct {
+		name string
+		// onWrite runs after each batch is written
+		onWrite     func(mr *miniredis.Miniredis)
+		wantWrites  int
+		wantFlushed int
+		wantErr     bool
+	}{
+		{
+			name: "batches outlasting the lock TTL together",
+			// Each batch takes most of the TTL
+			onWrite:     func(mr *miniredis.Miniredis) { mr.FastForward(lockTTL - time.Second) },
+			wantWrites:  3,
+			wantFlushed: queued,
+		},
+		{
+			name: "lock taken over after a batch",
+			onWrite: func(mr *miniredis.Miniredis) {
+				mr.FastForward(lockTTL)
+				mr.Set(lockKey, "another flusher")
+			},
+			wantWrites:  1,
+			wantFlushed: batchSize,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			t.Cleanup(func() { rdb.Close() })
+
+			tracker := NewTracker(rdb)
+			for id := int64(1); id <= int64(queued); id++ {
+				if err := tracker.QueueView(ctx, id); err != nil {
+					t.Fatalf("QueueView(%d): %v", id, err)
+				}
+			}
+
+			st := &slowStore{deltas: map[int64]int{}, onWrite: func() { tt.onWrite(mr) }}
+			f, err := NewFlusher(rdb, st, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if err != nil {
+				t.Fatalf("NewFlusher: %v", err)
+			}
+			n, err := f.Flush(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Flush error = %v, want error %v", err, tt.wantErr)
+			}
+			if n != tt.wantFlushed || st.writes != tt.wantWrites || len(st.deltas) != tt.wantFlushed {
+				t.Errorf("flushed %d question(s) in %d write(s), store has %d; want %d in %d",
+					n, st.writes, len(st.deltas), tt.wantFlushed, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package counters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRollupInterval is how often RunRollup saves daily stats unless
+// configured otherwise
+const DefaultRollupInterval = 5 * time.Minute
+
+// statsRetentionDays is how many days of views stay in Redis, so a rollup
+// that was down for a while can still catch up
+const statsRetentionDays = 8
+
+// VisitorsTTL is how long the visitors of a day are kept
+const VisitorsTTL = statsRetentionDays * 24 * time.Hour
+
+// dayLayout formats days in analytics keys
+const dayLayout = "20060102"
+
+// VisitorsKey returns the key of the HyperLogLog counting a question's
+// unique visitors on a UTC day
+func VisitorsKey(questionID int64, day time.Time) string {
+	return fmt.Sprintf("question:%d:visitors:%s", questionID, day.UTC().Format(dayLayout))
+}
+
+// dailyHitsKey returns the key of the hash counting every question's hits,
+// its page loads, on a UTC day
+func dailyHitsKey(day time.Time) string {
+	return "questions:views:" + day.UTC().Format(dayLayout)
+}
+
+// RecordVisit adds a visit by visitor to the question's analytics for the day
+// of now and reports whether the visitor is new for the day; like any
+// HyperLogLog answer it is approximate
+func (t *Tracker) RecordVisit(ctx context.Context, questionID int64, visitor string, now time.Time) (bool, error) {
+	pipe := t.redis.TxPipeline()
+	visitors := VisitorsKey(questionID, now)
+	added := pipe.PFAdd(ctx, visitors, visitor)
+	pipe.Expire(ctx, visitors, VisitorsTTL)
+
+	hits := dailyHitsKey(now)
+	pipe.HIncrBy(ctx, hits, strconv.FormatInt(questionID, 10), 1)
+	pipe.Expire(ctx, hits, VisitorsTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return added.Val() == 1, nil
+}
+
+// Rollup periodically saves the daily hits and unique visitors kept in
+// Redis to the store. Every save writes absolute values, so rolling a day up
+// again, or from several servers, is harmless.
+type Rollup struct {
+	redis    *redis.Client
+	store    store.StatsStore
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewRollup creates a Rollup saving to st every interval. A nil logger logs to
+// the default logger.
+func NewRollup(rdb *redis.Client, st store.StatsStore, interval time.Duration, logger *slog.Logger) *Rollup {
+	return &Rollup{redis: rdb, store: st, interval: interval, logger: logging.OrDefault(logger)}
+}
+
+// Run rolls up once at start, then every interval until ctx is done, and a
+// last time before returning
+func (r *Rollup) Run(ctx context.Context) {
+	r.rollUpAndLog(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.rollUpAndLog(ctx)
+		case <-ctx.Done():
+			final, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			r.rollUpAndLog(final)
+			cancel()
+			return
+		}
+	}
+}
+
+// rollUpAndLog runs RollUp and logs failures
+func (r *Rollup) rollUpAndLog(ctx context.Context) {
+	ctx, span := tracer.Start(ctx, "roll up view stats")
+	defer span.End()
+
+	if _, err := r.RollUp(ctx, time.Now()); err != nil {
+		r.logger.Error("rolling up view stats failed", "error", err)
+	}
+}
+
+// RollUp saves the stats of every day still kept in Redis up to the day of
+// now and returns how many question-days were saved
+func (r *Rollup) RollUp(ctx context.Context, now time.Time) (int, error) {
+	saved := 0
+	for i := statsRetentionDays - 1; i >= 0; i-- {
+		day := now.UTC().AddDate(0, 0, -i)
+		n, err := r.rollUpDay(ctx, day)
+		if err != nil {
+			return saved, err
+		}
+		saved += n
+	}
+	return saved, nil
+}
+
+// rollUpDay saves one day's stats in batches
+func (r *Rollup) rollUpDay(ctx context.Context, day time.Time) (int, error) {
+	counts, err := r.redis.HGetAll(ctx, dailyHitsKey(day)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hits of %s: %w", day.Format(dayLayout), err)
+	}
+
+	ids := make([]int64, 0, len(counts))
+	for field := range counts {
+		if id, err := strconv.ParseInt(field, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+
+		pipe := r.redis.Pipeline()
+		visitors := make([]*redis.IntCmd, len(batch))
+		for i, id := range batch {
+			visitors[i] = pipe.PFCount(ctx, VisitorsKey(id, day))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return start, fmt.Errorf("failed to count visitors of %s: %w", day.Format(dayLayout), err)
+		}
+
+		stats := make(map[int64]store.DailyViews, len(batch))
+		for i, id := range batch {
+			n, _ := strconv.Atoi(counts[strconv.FormatInt(id, 10)])
+			stats[id] = store.DailyViews{Hits: n, UniqueVisitors: int(visitors[i].Val())}
+		}
+		if err := r.store.SaveViewStats(ctx, day, stats); err != nil {
+			return start, err
+		}
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	// Versions run from 1 without gaps, and every migration can be reverted
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d_%s is number %d; versions must be consecutive from 1", m.Version, m.Name, i+1)
+		}
+		if m.Name == "" {
+			t.Errorf("migration %d has no name", m.Version)
+		}
+		if len(splitStatements(m.Up)) == 0 {
+			t.Errorf("migration %d_%s has an empty up script", m.Version, m.Name)
+		}
+		if len(splitStatements(m.Down)) == 0 {
+			t.Errorf("migration %d_%s has an empty down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"only comments and whitespace", "-- nothing\n# to do\n ;\n", nil},
+		{"statements", "CREATE TABLE a (id INT);\nDROP TABLE b;", []string{"CREATE TABLE a (id INT)", "DROP TABLE b"}},
+		{"no trailing semicolon", "SELECT 1", []string{"SELECT 1"}},
+		{
+			"semicolons in quotes",
+			"INSERT INTO t VALUES ('a;b', \"c;d\");\nALTER TABLE `x;y` ADD z INT;",
+			[]string{"INSERT INTO t VALUES ('a;b', \"c;d\")", "ALTER TABLE `x;y` ADD z INT"},
+		},
+		{"escaped quote", `INSERT INTO t VALUES ('it\'s; fine');`, []string{`INSERT INTO t VALUES ('it\'s; fine')`}},
+		{
+			"comments",
+			"-- drop; everything\nDROP TABLE a; # and; this\nDROP TABLE b;",
+			[]string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{"comment markers in quotes", "SELECT '-- # kept';", []string{"SELECT '-- # kept'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
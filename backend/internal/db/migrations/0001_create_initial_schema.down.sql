@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS question_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS likes;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS questions;
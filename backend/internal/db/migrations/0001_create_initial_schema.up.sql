@@ -0,0 +1,60 @@
+-- Initial schema. Tables are created only if missing so databases that were
+-- bootstrapped from schema.sql can adopt migrations without changes.
+
+CREATE TABLE IF NOT EXISTS questions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    view_count INT DEFAULT 0,
+    like_count INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_questions_created_at (created_at),
+    INDEX idx_questions_like_count (like_count),
+    INDEX idx_questions_view_count (view_count)
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    question_id INT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    INDEX idx_comments_question_id (question_id)
+);
+
+CREATE TABLE IF NOT EXISTS likes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    question_id INT NOT NULL,
+    client_ip VARCHAR(45) NOT NULL, -- IPv6 addresses can be up to 45 chars
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    -- Each like should be unique per IP address
+    UNIQUE KEY unique_like (question_id, client_ip),
+    INDEX idx_likes_question_id (question_id)
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS question_tags (
+    question_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (question_id, tag_id),
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+INSERT IGNORE INTO tags (name) VALUES
+('technology'),
+('programming'),
+('health'),
+('science'),
+('education'),
+('business'),
+('finance'),
+('travel'),
+('food'),
+('entertainment');
@@ -0,0 +1,17 @@
+DELETE FROM likes WHERE client_ip IS NULL;
+
+ALTER TABLE likes
+    DROP FOREIGN KEY fk_likes_author,
+    DROP INDEX unique_user_like,
+    DROP COLUMN author_id,
+    MODIFY COLUMN client_ip VARCHAR(45) NOT NULL;
+
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_author,
+    DROP COLUMN author_id;
+
+ALTER TABLE questions
+    DROP FOREIGN KEY fk_questions_author,
+    DROP COLUMN author_id;
+
+DROP TABLE IF EXISTS users;
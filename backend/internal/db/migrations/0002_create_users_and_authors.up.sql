@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE questions
+    ADD COLUMN author_id INT NULL AFTER content,
+    ADD CONSTRAINT fk_questions_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE comments
+    ADD COLUMN author_id INT NULL AFTER content,
+    ADD CONSTRAINT fk_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Signed-in users like by account, anonymous visitors by IP address, so
+-- client_ip becomes optional and each user may like a question once
+ALTER TABLE likes
+    MODIFY COLUMN client_ip VARCHAR(45) NULL,
+    ADD COLUMN author_id INT NULL AFTER client_ip,
+    ADD CONSTRAINT fk_likes_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    ADD UNIQUE KEY unique_user_like (question_id, author_id);
@@ -0,0 +1,10 @@
+DELETE FROM comments WHERE answer_id IS NOT NULL;
+
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_answer,
+    DROP COLUMN answer_id;
+
+ALTER TABLE questions
+    DROP COLUMN answer_count;
+
+DROP TABLE IF EXISTS answers;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS answers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    question_id INT NOT NULL,
+    author_id INT NULL,
+    content TEXT NOT NULL,
+    score INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_answers_question_score (question_id, score),
+    INDEX idx_answers_question_created_at (question_id, created_at)
+);
+
+-- Denormalized so the question list can show answer counts without a join
+ALTER TABLE questions
+    ADD COLUMN answer_count INT NOT NULL DEFAULT 0 AFTER like_count;
+
+-- Comments belong to a question and, when answer_id is set, to one of its answers
+ALTER TABLE comments
+    ADD COLUMN answer_id INT NULL AFTER question_id,
+    ADD CONSTRAINT fk_comments_answer FOREIGN KEY (answer_id) REFERENCES answers(id) ON DELETE CASCADE;
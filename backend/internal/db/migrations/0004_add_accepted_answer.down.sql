@@ -0,0 +1,4 @@
+ALTER TABLE questions
+    DROP FOREIGN KEY fk_questions_accepted_answer,
+    DROP INDEX idx_questions_answer_count,
+    DROP COLUMN accepted_answer_id;
@@ -0,0 +1,4 @@
+ALTER TABLE questions
+    ADD COLUMN accepted_answer_id INT NULL AFTER answer_count,
+    ADD CONSTRAINT fk_questions_accepted_answer FOREIGN KEY (accepted_answer_id) REFERENCES answers(id) ON DELETE SET NULL,
+    ADD INDEX idx_questions_answer_count (answer_count);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS question_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    question_id INT NOT NULL,
+    revision INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    tags JSON NOT NULL,
+    editor_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE KEY unique_question_revision (question_id, revision)
+);
+
+-- Existing questions start their history with their current state as revision 1
+INSERT IGNORE INTO question_revisions (question_id, revision, title, content, tags, editor_id, created_at)
+SELECT q.id, 1, q.title, q.content,
+    COALESCE((
+        SELECT JSON_ARRAYAGG(t.name)
+        FROM question_tags qt
+        JOIN tags t ON t.id = qt.tag_id
+        WHERE qt.question_id = q.id
+    ), JSON_ARRAY()),
+    q.author_id, q.updated_at
+FROM questions q;
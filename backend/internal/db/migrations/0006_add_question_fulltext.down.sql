@@ -0,0 +1,4 @@
+ALTER TABLE questions
+    DROP INDEX ft_questions_title_content,
+    DROP INDEX ft_questions_title,
+    DROP INDEX ft_questions_content;
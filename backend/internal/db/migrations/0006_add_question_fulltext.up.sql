@@ -0,0 +1,6 @@
+-- Search filters on the combined index and ranks with the per-column indexes,
+-- so title matches can be weighted above body matches. InnoDB builds one
+-- FULLTEXT index per statement.
+ALTER TABLE questions ADD FULLTEXT INDEX ft_questions_title_content (title, content);
+ALTER TABLE questions ADD FULLTEXT INDEX ft_questions_title (title);
+ALTER TABLE questions ADD FULLTEXT INDEX ft_questions_content (content);
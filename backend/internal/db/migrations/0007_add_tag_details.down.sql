@@ -0,0 +1,3 @@
+ALTER TABLE tags
+    DROP COLUMN created_at,
+    DROP COLUMN description;
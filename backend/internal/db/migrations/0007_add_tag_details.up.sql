@@ -0,0 +1,4 @@
+-- description holds the tag wiki text shown in the tag directory
+ALTER TABLE tags
+    ADD COLUMN description TEXT NULL AFTER name,
+    ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP AFTER description;
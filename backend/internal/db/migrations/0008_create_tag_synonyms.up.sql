@@ -0,0 +1,8 @@
+-- Maps alternative spellings such as "golang" to their canonical tag
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+    alias VARCHAR(50) NOT NULL PRIMARY KEY,
+    tag_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+    INDEX idx_tag_synonyms_tag (tag_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE questions
+    DROP INDEX idx_questions_updated_at;
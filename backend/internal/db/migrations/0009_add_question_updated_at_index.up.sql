@@ -0,0 +1,3 @@
+-- Keyset pagination by updated_at seeks on this index like the other sort columns
+ALTER TABLE questions
+    ADD INDEX idx_questions_updated_at (updated_at);
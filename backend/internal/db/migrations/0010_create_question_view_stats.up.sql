@@ -0,0 +1,11 @@
+-- Daily views and approximate unique visitors per question, rolled up from
+-- Redis. Likes per day are counted from the likes table when read.
+CREATE TABLE IF NOT EXISTS question_view_stats (
+    question_id INT NOT NULL,
+    day DATE NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    unique_visitors INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (question_id, day),
+    FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Moderators may edit, delete and roll back any question and answer,
+-- including those posted anonymously or before authors were recorded
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' AFTER password_hash;
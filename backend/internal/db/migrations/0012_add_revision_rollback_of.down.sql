@@ -0,0 +1,2 @@
+ALTER TABLE question_revisions
+    DROP COLUMN rollback_of;
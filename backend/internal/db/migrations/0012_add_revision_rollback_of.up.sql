@@ -0,0 +1,4 @@
+-- Revisions created by rolling a question back record the revision they
+-- restored; the rollback's editor_id records who performed it
+ALTER TABLE question_revisions
+    ADD COLUMN rollback_of INT NULL AFTER editor_id;
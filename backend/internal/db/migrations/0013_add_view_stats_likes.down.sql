@@ -0,0 +1,2 @@
+ALTER TABLE question_view_stats
+    DROP COLUMN likes;
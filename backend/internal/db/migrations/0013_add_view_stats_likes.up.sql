@@ -0,0 +1,14 @@
+-- Likes given per UTC day, recorded with each like so unliking later does
+-- not rewrite past days. Existing likes are counted once from the likes
+-- table; likes removed before this migration are lost.
+ALTER TABLE question_view_stats
+    ADD COLUMN likes INT NOT NULL DEFAULT 0 AFTER unique_visitors;
+
+INSERT INTO question_view_stats (question_id, day, likes)
+SELECT l.question_id, l.day, l.likes
+FROM (
+    SELECT question_id, DATE(CONVERT_TZ(created_at, @@session.time_zone, '+00:00')) AS day, COUNT(*) AS likes
+    FROM likes
+    GROUP BY question_id, day
+) l
+ON DUPLICATE KEY UPDATE likes = l.likes;
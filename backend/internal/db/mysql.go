@@ -3,29 +3,41 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"os"
+	"strconv"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/XSAM/otelsql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/questions/backend/internal/config"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 // DB is the global database connection
 var DB *sql.DB
 
 // InitMySQL initializes the MySQL database connection
-func InitMySQL() error {
-	// Get MySQL configuration from environment variables
-	host := os.Getenv("MYSQL_HOST")
-	port := os.Getenv("MYSQL_PORT")
-	user := os.Getenv("MYSQL_USER")
-	password := os.Getenv("MYSQL_PASSWORD")
-	dbName := os.Getenv("MYSQL_DATABASE")
-
+func InitMySQL(cfg config.MySQLConfig) error {
 	// Construct the MySQL DSN (Data Source Name)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, password, host, port, dbName)
+	dsn := mysql.NewConfig()
+	dsn.User = cfg.User
+	dsn.Passwd = cfg.Password
+	dsn.Net = "tcp"
+	dsn.Addr = cfg.Host + ":" + strconv.Itoa(cfg.Port)
+	dsn.DBName = cfg.Database
+	dsn.ParseTime = true
+	dsn.Timeout = cfg.DialTimeout
+	dsn.ReadTimeout = cfg.ReadTimeout
+	dsn.WriteTimeout = cfg.WriteTimeout
 
-	// Open database connection
+	// Open database connection, tracing every query under the span of its
+	// context. Spans record the SQL text, never the arguments.
 	var err error
-	DB, err = sql.Open("mysql", dsn)
+	DB, err = otelsql.Open("mysql", dsn.FormatDSN(),
+		otelsql.WithAttributes(semconv.DBSystemMySQL, semconv.DBNamespace(cfg.Database)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}))
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
@@ -36,8 +48,9 @@ func InitMySQL() error {
 	}
 
 	// Configure connection pool
-	DB.SetMaxOpenConns(25)
-	DB.SetMaxIdleConns(5)
+	DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return nil
 }
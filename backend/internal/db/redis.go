@@ -3,38 +3,32 @@ package db
 import (
 	"context"
 	"fmt"
-	"os"
 	"strconv"
 
+	"github.com/questions/backend/internal/config"
 	"github.com/redis/go-redis/v9"
 )
 
 // Redis is the global Redis client
 var Redis *redis.Client
 
-// InitRedis initializes the Redis connection
-func InitRedis() error {
-	// Get Redis configuration from environment variables
-	host := os.Getenv("REDIS_HOST")
-	port := os.Getenv("REDIS_PORT")
-	password := os.Getenv("REDIS_PASSWORD")
-	dbStr := os.Getenv("REDIS_DB")
-
-	// Parse DB number
-	db, err := strconv.Atoi(dbStr)
-	if err != nil {
-		db = 0 // Default to DB 0 if parsing fails
-	}
-
+// InitRedis initializes the Redis connection. The client is created even
+// when Redis does not answer, so callers may keep it and retry later.
+func InitRedis(cfg config.RedisConfig) error {
 	// Create Redis client
 	Redis = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", host, port),
-		Password: password,
-		DB:       db,
+		Addr:         cfg.Host + ":" + strconv.Itoa(cfg.Port),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	})
 
 	// Verify the connection
-	_, err = Redis.Ping(context.Background()).Result()
+	_, err := Redis.Ping(context.Background()).Result()
 	if err != nil {
 		return fmt.Errorf("failed to connect to Redis: %v", err)
 	}
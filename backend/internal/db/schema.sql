@@ -1,3 +1,7 @@
+-- Development bootstrap used by docker-compose: creates the initial schema with
+-- sample data. The authoritative schema lives in migrations/; run
+-- `migrate up` afterwards to bring the database to the latest version.
+
 -- Drop existing tables if they exist (for clean initialization)
 DROP TABLE IF EXISTS question_tags;
 DROP TABLE IF EXISTS tags;
@@ -0,0 +1,147 @@
+// Package diff computes line-level differences between two texts
+package diff
+
+import "strings"
+
+// Line operations reported in a diff
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Line is one line of a diff together with how it changed
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line-level diff that turns a into b. Unchanged lines are
+// included so the result can be rendered as a full side-by-side or unified view.
+func Lines(a, b string) []Line {
+	return Slices(splitLines(a), splitLines(b))
+}
+
+// maxEdits bounds the number of inserted and deleted lines Slices searches
+// for. Texts further apart are diffed as a deletion of every changed line
+// followed by an insertion of every new one, keeping the work on large
+// rewrites linear in their size.
+const maxEdits = 1000
+
+// Slices returns the diff that turns the lines of a into the lines of b with
+// the fewest inserted and deleted lines, using Myers' O(ND) algorithm
+func Slices(a, b []string) []Line {
+	// Trim the common prefix and suffix so the search only covers the changed middle
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		result = append(result, Line{Op: OpEqual, Text: line})
+	}
+	result = append(result, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: OpEqual, Text: line})
+	}
+	return result
+}
+
+// myersDiff diffs a and b with Myers' greedy shortest edit script search,
+// replacing all of a with all of b when they are more than maxEdits apart.
+// It keeps the furthest reaching path of every diagonal for every edit
+// distance tried, so it needs O(D²) memory for a distance of D.
+func myersDiff(a, b []string) []Line {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+
+	// v[offset+k] is the furthest x reached on diagonal k = x-y
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	// trace[d] holds v for the diagonals -d..d before searching distance d
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // insert b[y-1]
+			} else {
+				x = v[offset+k-1] + 1 // delete a[x-1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	result := make([]Line, 0, n+m)
+	for _, line := range a {
+		result = append(result, Line{Op: OpDelete, Text: line})
+	}
+	for _, line := range b {
+		result = append(result, Line{Op: OpInsert, Text: line})
+	}
+	return result
+}
+
+// backtrack walks the paths recorded by myersDiff back from the end of a and
+// b and returns the diff they describe
+func backtrack(a, b []string, trace [][]int) []Line {
+	result := make([]Line, 0, len(a)+len(b))
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		// v returns the furthest x on diagonal k after distance d-1
+		v := func(k int) int { return trace[d][k+d] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			result = append(result, Line{Op: OpEqual, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			result = append(result, Line{Op: OpInsert, Text: b[y]})
+		} else {
+			x--
+			result = append(result, Line{Op: OpDelete, Text: a[x]})
+		}
+	}
+	for x > 0 {
+		x--
+		result = append(result, Line{Op: OpEqual, Text: a[x]})
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// splitLines splits text into lines, treating CRLF like LF. Empty text has no lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
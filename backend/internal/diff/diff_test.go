@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// eq, ins and del build expected diff lines
+func eq(text string) Line  { return Line{Op: OpEqual, Text: text} }
+func ins(text string) Line { return Line{Op: OpInsert, Text: text} }
+func del(text string) Line { return Line{Op: OpDelete, Text: text} }
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{"both empty", "", "", []Line{}},
+		{"unchanged", "a\nb", "a\nb", []Line{eq("a"), eq("b")}},
+		{"created", "", "a\nb", []Line{ins("a"), ins("b")}},
+		{"cleared", "a\nb", "", []Line{del("a"), del("b")}},
+		{"line changed", "a\nb\nc", "a\nB\nc", []Line{eq("a"), del("b"), ins("B"), eq("c")}},
+		{"line inserted", "a\nc", "a\nb\nc", []Line{eq("a"), ins("b"), eq("c")}},
+		{"line removed", "a\nb\nc", "a\nc", []Line{eq("a"), del("b"), eq("c")}},
+		{"lines moved", "a\nb\nc\nd", "c\nd\na\nb", []Line{del("a"), del("b"), eq("c"), eq("d"), ins("a"), ins("b")}},
+		{"CRLF and trailing newline", "a\r\nb\r\n", "a\nb", []Line{eq("a"), eq("b")}},
+		{"blank lines", "a\n\nb", "a\nb", []Line{eq("a"), del(""), eq("b")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlicesApplies(t *testing.T) {
+	// Replaying any diff's equal and deleted lines yields a, and its equal
+	// and inserted lines yields b
+	pairs := [][2]string{
+		{"the quick brown fox", "the slow brown dog"},
+		{"a b c d e f g", "b c x e g h"},
+		{"x x x y", "y x x"},
+		{"", "new"},
+	}
+	for _, pair := range pairs {
+		a, b := strings.Fields(pair[0]), strings.Fields(pair[1])
+		var gotA, gotB []string
+		for _, line := range Slices(a, b) {
+			if line.Op != OpInsert {
+				gotA = append(gotA, line.Text)
+			}
+			if line.Op != OpDelete {
+				gotB = append(gotB, line.Text)
+			}
+		}
+		if strings.Join(gotA, " ") != pair[0] || strings.Join(gotB, " ") != pair[1] {
+			t.Errorf("Slices(%q, %q) replays to %q and %q", a, b, gotA, gotB)
+		}
+	}
+}
+
+func TestSlicesLarge(t *testing.T) {
+	const n = 50000
+	a := make([]string, n)
+	for i := range a {
+		a[i] = fmt.Sprintf("line %d", i)
+	}
+
+	// Changing the first and last lines leaves nothing to trim, but the
+	// diff stays minimal
+	b := append([]string{"first"}, a[1:n-1]...)
+	b = append(b, "last")
+	got := Slices(a, b)
+	if len(got) != n+2 || got[0] != del("line 0") || got[1] != ins("first") ||
+		got[n] != del(fmt.Sprintf("line %d", n-1)) || got[n+1] != ins("last") {
+		t.Errorf("diff of %d lines with changed ends has %d lines, starting %v and ending %v", n, len(got), got[:2], got[len(got)-2:])
+	}
+
+	// Texts more than maxEdits apart are replaced as a whole
+	c := make([]string, n)
+	for i := range c {
+		c[i] = fmt.Sprintf("rewritten %d", i)
+	}
+	c[n/2] = a[n/2]
+	got = Slices(a, c)
+	if len(got) != 2*n {
+		t.Fatalf("diff of two rewrites has %d lines, want %d", len(got), 2*n)
+	}
+	for i, line := range got {
+		if want := i < n; (line.Op == OpDelete) != want {
+			t.Fatalf("line %d of a rewrite diff is %v; want every deletion before every insertion", i, line)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build identifies the running binary
+type Build struct {
+	Version string
+	Commit  string
+	// Modified reports uncommitted changes in the tree the binary was built from
+	Modified  bool
+	GoVersion string
+}
+
+// NewBuild describes the running binary. version and commit are set at link
+// time; an empty commit is read from the VCS stamp that go build embeds when
+// building from a git checkout.
+func NewBuild(version, commit string) Build {
+	build := Build{Version: version, Commit: commit, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if build.Commit == "" {
+					build.Commit = s.Value
+				}
+			case "vcs.modified":
+				build.Modified = s.Value == "true"
+			}
+		}
+	}
+	if build.Version == "" {
+		build.Version = "dev"
+	}
+	if build.Commit == "" {
+		build.Commit = "unknown"
+	}
+	return build
+}
@@ -0,0 +1,179 @@
+// Package health serves the liveness, readiness and status endpoints used by
+// process managers, container runtimes and load balancers
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Checker pings the server's dependencies and reports its build, uptime and
+// schema version. It reports not ready once Drain is called.
+type Checker struct {
+	build   Build
+	started time.Time
+	timeout time.Duration
+	checks  []check
+	schema  func(ctx context.Context) (current, latest int, err error)
+
+	draining atomic.Bool
+}
+
+// check pings one dependency
+type check struct {
+	name     string
+	required bool
+	ping     func(ctx context.Context) error
+}
+
+// Result is the outcome of pinging one dependency
+type Result struct {
+	Status string `json:"status"`
+	// Required dependencies fail readiness when down; the server keeps
+	// serving without the others
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// New creates a Checker for build that gives each ping timeout to answer
+func New(build Build, timeout time.Duration) *Checker {
+	return &Checker{build: build, started: time.Now(), timeout: timeout}
+}
+
+// Add registers a dependency; readiness fails while a required one is down
+func (c *Checker) Add(name string, required bool, ping func(ctx context.Context) error) {
+	c.checks = append(c.checks, check{name: name, required: required, ping: ping})
+}
+
+// Schema sets how /status reads the applied and latest migration versions
+func (c *Checker) Schema(version func(ctx context.Context) (current, latest int, err error)) {
+	c.schema = version
+}
+
+// Drain makes readiness fail from now on, as the server is shutting down
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// run pings every dependency concurrently and reports whether all required
+// ones are up
+func (c *Checker) run(ctx context.Context) (map[string]Result, bool) {
+	results := make(map[string]Result, len(c.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := chk.ping(ctx)
+			res := Result{
+				Status:    "up",
+				Required:  chk.required,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				res.Status = "down"
+				res.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[chk.name] = res
+			mu.Unlock()
+		}(chk)
+	}
+	wg.Wait()
+
+	ready := true
+	for _, res := range results {
+		if res.Required && res.Status != "up" {
+			ready = false
+		}
+	}
+	return results, ready
+}
+
+// Live answers as long as the process serves HTTP; it checks no dependency,
+// so a restart cannot fix what it reports
+func (c *Checker) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready reports whether the server should receive traffic: it is not
+// shutting down and every required dependency answers its ping
+func (c *Checker) Ready(ctx *gin.Context) {
+	if c.Draining() {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+		return
+	}
+
+	results, ready := c.run(ctx.Request.Context())
+	if !ready {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "checks": results})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ready", "checks": results})
+}
+
+// Status reports the build, uptime, schema version and dependencies of the
+// server. Its status is ok, degraded while an optional dependency is down,
+// unavailable while a required one is, or shutting down; it always answers
+// 200 as it describes the server rather than gating traffic.
+func (c *Checker) Status(ctx *gin.Context) {
+	results, ready := c.run(ctx.Request.Context())
+
+	status := "ok"
+	for _, res := range results {
+		if res.Status != "up" {
+			status = "degraded"
+		}
+	}
+	if !ready {
+		status = "unavailable"
+	}
+	if c.Draining() {
+		status = "shutting down"
+	}
+
+	uptime := time.Since(c.started)
+	body := gin.H{
+		"status":         status,
+		"version":        c.build.Version,
+		"commit":         c.build.Commit,
+		"modified":       c.build.Modified,
+		"go_version":     c.build.GoVersion,
+		"started_at":     c.started.UTC().Format(time.RFC3339),
+		"uptime":         uptime.Truncate(time.Second).String(),
+		"uptime_seconds": int64(uptime.Seconds()),
+		"dependencies":   results,
+	}
+
+	if c.schema != nil {
+		schemaCtx, cancel := context.WithTimeout(ctx.Request.Context(), c.timeout)
+		defer cancel()
+		current, latest, err := c.schema(schemaCtx)
+		migrations := gin.H{"current": current, "latest": latest}
+		if err != nil {
+			migrations["error"] = err.Error()
+		} else {
+			migrations["pending"] = latest - current
+		}
+		body["migrations"] = migrations
+	}
+
+	ctx.JSON(http.StatusOK, body)
+}
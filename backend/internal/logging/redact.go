@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+)
+
+// Masked prefix lengths of client addresses: enough to tell networks apart,
+// not enough to identify a client
+const (
+	ipv4MaskBits = 24
+	ipv6MaskBits = 48
+)
+
+// ClientIP is an attribute holding a client address with its host part
+// zeroed, e.g. 203.0.113.0/24
+func ClientIP(ip string) slog.Attr {
+	return slog.String("client_ip", MaskIP(ip))
+}
+
+// MaskIP zeroes the host part of ip. Values that are not addresses are
+// replaced entirely.
+func MaskIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "redacted"
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return fmt.Sprintf("%s/%d", v4.Mask(net.CIDRMask(ipv4MaskBits, 32)), ipv4MaskBits)
+	}
+	return fmt.Sprintf("%s/%d", parsed.Mask(net.CIDRMask(ipv6MaskBits, 128)), ipv6MaskBits)
+}
+
+// Args is an attribute holding query arguments, logged as their types and
+// sizes so user input such as search terms never reaches the logs
+func Args(args ...interface{}) slog.Attr {
+	return slog.Any("args", redactedArgs(args))
+}
+
+// redactedArgs logs arguments without their values
+type redactedArgs []interface{}
+
+// LogValue implements slog.LogValuer
+func (a redactedArgs) LogValue() slog.Value {
+	described := make([]string, len(a))
+	for i, arg := range a {
+		switch v := arg.(type) {
+		case string:
+			described[i] = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			described[i] = fmt.Sprintf("[]byte(%d)", len(v))
+		case []string:
+			described[i] = fmt.Sprintf("[]string(%d)", len(v))
+		case nil:
+			described[i] = "nil"
+		default:
+			described[i] = fmt.Sprintf("%T", v)
+		}
+	}
+	return slog.AnyValue(described)
+}
@@ -0,0 +1,121 @@
+// Package metrics exposes Prometheus metrics of the HTTP API, the MySQL and
+// Redis connection pools, the cache and business events
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the server's collectors in its own registry. Its event and
+// Observe methods do nothing on a nil Metrics, so components can run without
+// metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+
+	questionsCreated prometheus.Counter
+	commentsAdded    *prometheus.CounterVec
+	likesToggled     *prometheus.CounterVec
+	viewsRecorded    prometheus.Counter
+	rateLimited      *prometheus.CounterVec
+}
+
+// New creates Metrics with the HTTP and business collectors and the Go
+// runtime and process collectors registered
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests by route, method and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests being served.",
+		}),
+		questionsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "questions_created_total",
+			Help: "Questions created.",
+		}),
+		commentsAdded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "comments_added_total",
+			Help: "Comments added, by whether they are on a question or an answer.",
+		}, []string{"on"}),
+		likesToggled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "likes_toggled_total",
+			Help: "Question likes toggled, by whether the like was added or removed.",
+		}, []string{"action"}),
+		viewsRecorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "question_views_recorded_total",
+			Help: "Question views counted, at most one per client per question per day.",
+		}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rate_limited_requests_total",
+			Help: "Requests rejected for exceeding their rate limit, by limited route.",
+		}, []string{"route"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.inFlight,
+		m.questionsCreated, m.commentsAdded, m.likesToggled, m.viewsRecorded, m.rateLimited,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// QuestionCreated counts a created question
+func (m *Metrics) QuestionCreated() {
+	if m != nil {
+		m.questionsCreated.Inc()
+	}
+}
+
+// CommentAdded counts a comment on a question or an answer
+func (m *Metrics) CommentAdded(onAnswer bool) {
+	if m == nil {
+		return
+	}
+	on := "question"
+	if onAnswer {
+		on = "answer"
+	}
+	m.commentsAdded.WithLabelValues(on).Inc()
+}
+
+// LikeToggled counts a like added or removed
+func (m *Metrics) LikeToggled(liked bool) {
+	if m == nil {
+		return
+	}
+	action := "removed"
+	if liked {
+		action = "added"
+	}
+	m.likesToggled.WithLabelValues(action).Inc()
+}
+
+// ViewRecorded counts a view that increments a question's view count
+func (m *Metrics) ViewRecorded() {
+	if m != nil {
+		m.viewsRecorded.Inc()
+	}
+}
+
+// RateLimited counts a request rejected by the limit of route
+func (m *Metrics) RateLimited(route string) {
+	if m != nil {
+		m.rateLimited.WithLabelValues(route).Inc()
+	}
+}
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/questions/backend/internal/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// ObserveDB exports the connection pool stats of db as go_sql_* metrics
+// labeled with name
+func (m *Metrics) ObserveDB(db *sql.DB, name string) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRedis exports the connection pool stats of rdb and times every
+// command it runs
+func (m *Metrics) ObserveRedis(rdb *redis.Client) {
+	if m == nil {
+		return
+	}
+	hook := &redisHook{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "redis_command_duration_seconds",
+			Help: "Duration of Redis commands by command; pipelines and transactions count as one.",
+			// Redis answers in well under a millisecond unless something is wrong
+			Buckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_command_errors_total",
+			Help: "Redis commands that failed, by command; missing keys are not failures.",
+		}, []string{"command"}),
+	}
+	rdb.AddHook(hook)
+
+	stats := func(value func(*redis.PoolStats) uint32) func() float64 {
+		return func() float64 { return float64(value(rdb.PoolStats())) }
+	}
+	m.registry.MustRegister(
+		hook.duration, hook.errors,
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redis_pool_hits_total",
+			Help: "Times a free connection was found in the Redis pool.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.Hits })),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redis_pool_misses_total",
+			Help: "Times no free connection was found in the Redis pool.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.Misses })),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redis_pool_timeouts_total",
+			Help: "Times waiting for a Redis connection timed out.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.Timeouts })),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "redis_pool_connections",
+			Help: "Connections in the Redis pool.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.TotalConns })),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "redis_pool_idle_connections",
+			Help: "Idle connections in the Redis pool.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.IdleConns })),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redis_pool_stale_connections_total",
+			Help: "Stale connections removed from the Redis pool.",
+		}, stats(func(s *redis.PoolStats) uint32 { return s.StaleConns })),
+	)
+}
+
+// redisHook times Redis commands
+type redisHook struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// DialHook implements redis.Hook
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+		h.observe("dial", start, err)
+		return conn, err
+	}
+}
+
+// ProcessHook implements redis.Hook
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.observe(strings.ToLower(cmd.Name()), start, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		name := "pipeline"
+		if len(cmds) > 0 && cmds[0].Name() == "multi" {
+			name = "multi"
+		}
+		h.observe(name, start, err)
+		return err
+	}
+}
+
+// observe records one command that started at start
+func (h *redisHook) observe(command string, start time.Time, err error) {
+	h.duration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, redis.Nil) {
+		h.errors.WithLabelValues(command).Inc()
+	}
+}
+
+// ObserveCache exports the hit, miss and error counts and hit ratios of c
+// per kind of value, and whether backend has fallen back from Redis when it
+// is a *cache.Fallback
+func (m *Metrics) ObserveCache(c *cache.Versioned, backend cache.Backend) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(&cacheCollector{cache: c})
+	if fallback, ok := backend.(*cache.Fallback); ok {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cache_degraded",
+			Help: "1 while Redis is unavailable and the cache falls back to memory.",
+		}, func() float64 {
+			if fallback.Degraded() {
+				return 1
+			}
+			return 0
+		}))
+	}
+}
+
+// Descriptions of the cache metrics
+var (
+	cacheHitsDesc = prometheus.NewDesc("cache_hits_total",
+		"Cache reads answered from the cache, by kind of value.", []string{"kind"}, nil)
+	cacheMissesDesc = prometheus.NewDesc("cache_misses_total",
+		"Cache reads that loaded the value, by kind of value.", []string{"kind"}, nil)
+	cacheErrorsDesc = prometheus.NewDesc("cache_errors_total",
+		"Cache reads and writes that failed, by kind of value; failed reads also count as misses.", []string{"kind"}, nil)
+	cacheHitRatioDesc = prometheus.NewDesc("cache_hit_ratio",
+		"Share of cache reads answered from the cache since startup, by kind of value.", []string{"kind"}, nil)
+)
+
+// cacheCollector reads the stats of a Versioned cache on every scrape
+type cacheCollector struct {
+	cache *cache.Versioned
+}
+
+// Describe implements prometheus.Collector
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheErrorsDesc
+	ch <- cacheHitRatioDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for kind, stats := range c.cache.Stats() {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits), kind)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses), kind)
+		ch <- prometheus.MustNewConstMetric(cacheErrorsDesc, prometheus.CounterValue, float64(stats.Errors), kind)
+		if reads := stats.Hits + stats.Misses; reads > 0 {
+			ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, float64(stats.Hits)/float64(reads), kind)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Answer represents an answer to a question
+type Answer struct {
+	ID         int64     `json:"id" db:"id"`
+	QuestionID int64     `json:"question_id" db:"question_id"`
+	AuthorID   *int64    `json:"author_id" db:"author_id"`
+	Content    string    `json:"content" db:"content"`
+	Score      int       `json:"score" db:"score"`
+	IsAccepted bool      `json:"is_accepted" db:"-"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Comments   []Comment `json:"comments"`
+}
+
+// AnswerRequest represents the structure for creating or editing an answer
+type AnswerRequest struct {
+	Content string `json:"content" binding:"required"`
+}
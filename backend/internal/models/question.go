@@ -7,13 +7,25 @@ import (
 
 // Question represents a question asked by a user
 type Question struct {
-	ID        int64     `json:"id" db:"id"`
-	Title     string    `json:"title" db:"title"`
-	Content   string    `json:"content" db:"content"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	LikeCount int       `json:"like_count" db:"like_count"`
-	ViewCount int       `json:"view_count" db:"view_count"`
+	ID          int64     `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title"`
+	Content     string    `json:"content" db:"content"`
+	AuthorID    *int64    `json:"author_id" db:"author_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	LikeCount   int       `json:"like_count" db:"like_count"`
+	ViewCount   int       `json:"view_count" db:"view_count"`
+	AnswerCount int       `json:"answer_count" db:"answer_count"`
+
+	AcceptedAnswerID *int64 `json:"accepted_answer_id" db:"accepted_answer_id"`
+
+	// Relevance is the search score when the question was listed by a search query
+	Relevance float64 `json:"relevance,omitempty" db:"-"`
+}
+
+// IsAnswered reports whether the question has any answer or an accepted one
+func (q Question) IsAnswered() bool {
+	return q.AnswerCount > 0 || q.AcceptedAnswerID != nil
 }
 
 // MarshalJSON implements custom JSON marshalling for Question to support both field naming conventions
@@ -23,12 +35,14 @@ func (q Question) MarshalJSON() ([]byte, error) {
 	// Create a map with both field naming conventions
 	return json.Marshal(struct {
 		QuestionAlias
-		LikesCount int `json:"likes_count"`
-		ViewsCount int `json:"views_count"`
+		LikesCount int  `json:"likes_count"`
+		ViewsCount int  `json:"views_count"`
+		IsAnswered bool `json:"is_answered"`
 	}{
 		QuestionAlias: QuestionAlias(q),
 		LikesCount:    q.LikeCount,
 		ViewsCount:    q.ViewCount,
+		IsAnswered:    q.IsAnswered(),
 	})
 }
 
@@ -38,11 +52,21 @@ type Tag struct {
 	Name string `json:"name" db:"name"`
 }
 
-// Comment represents a comment on a question
+// TagInfo is a tag with its wiki description and usage, as listed in the tag directory
+type TagInfo struct {
+	Tag
+	Description   string    `json:"description" db:"description"`
+	QuestionCount int       `json:"question_count" db:"question_count"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Comment represents a short remark on a question, or on one of its answers when AnswerID is set
 type Comment struct {
 	ID         int64     `json:"id" db:"id"`
 	QuestionID int64     `json:"question_id" db:"question_id"`
+	AnswerID   *int64    `json:"answer_id" db:"answer_id"`
 	Content    string    `json:"content" db:"content"`
+	AuthorID   *int64    `json:"author_id" db:"author_id"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -52,10 +76,11 @@ type QuestionTag struct {
 	TagID      int64 `json:"tag_id" db:"tag_id"`
 }
 
-// Like represents a like on a question
+// Like represents a like on a question, made either by a signed-in user or an anonymous client IP
 type Like struct {
 	ID         int64     `json:"id" db:"id"`
 	QuestionID int64     `json:"question_id" db:"question_id"`
+	AuthorID   *int64    `json:"author_id" db:"author_id"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// QuestionRevision is a snapshot of a question's title, content and tags
+// taken every time the question is created, edited or rolled back
+type QuestionRevision struct {
+	ID         int64     `json:"id" db:"id"`
+	QuestionID int64     `json:"question_id" db:"question_id"`
+	Revision   int       `json:"revision" db:"revision"`
+	Title      string    `json:"title" db:"title"`
+	Content    string    `json:"content" db:"content"`
+	Tags       []string  `json:"tags" db:"tags"`
+	EditorID   *int64    `json:"editor_id" db:"editor_id"`
+	RollbackOf *int      `json:"rollback_of" db:"rollback_of"` // the restored revision, for rollbacks
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
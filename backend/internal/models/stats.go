@@ -0,0 +1,13 @@
+package models
+
+// StatsDateLayout is the format of the days in question stats
+const StatsDateLayout = "2006-01-02"
+
+// QuestionDayStats holds one UTC day of a question's analytics. Hits count
+// every page load; unique visitors count each client IP once, as view_count does.
+type QuestionDayStats struct {
+	Date           string `json:"date" db:"day"`
+	Hits           int    `json:"hits" db:"views"`
+	UniqueVisitors int    `json:"unique_visitors" db:"unique_visitors"`
+	Likes          int    `json:"likes"`
+}
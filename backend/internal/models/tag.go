@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the longest tag name accepted, in characters
+const MaxTagLength = 35
+
+// TagSynonym maps an alias to its canonical tag
+type TagSynonym struct {
+	Alias     string    `json:"alias" db:"alias"`
+	TagID     int64     `json:"tag_id" db:"tag_id"`
+	TagName   string    `json:"tag_name" db:"-"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NormalizeTagName returns the canonical spelling of a tag name: trimmed,
+// lowercased, with inner whitespace replaced by hyphens. Names may contain
+// letters, digits and the characters + # . - and must include at least one
+// letter or digit.
+func NormalizeTagName(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if normalized == "" {
+		return "", fmt.Errorf("tag name is empty")
+	}
+	if n := utf8.RuneCountInString(normalized); n > MaxTagLength {
+		return "", fmt.Errorf("tag %q is longer than %d characters", normalized, MaxTagLength)
+	}
+
+	hasAlphanumeric := false
+	for _, r := range normalized {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			hasAlphanumeric = true
+		case strings.ContainsRune("+#.-", r):
+		default:
+			return "", fmt.Errorf("tag %q contains invalid character %q", normalized, r)
+		}
+	}
+	if !hasAlphanumeric {
+		return "", fmt.Errorf("tag %q must contain a letter or digit", normalized)
+	}
+
+	return normalized, nil
+}
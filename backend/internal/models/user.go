@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// User represents a registered account
+type User struct {
+	ID           int64     `json:"id" db:"id"`
+	Username     string    `json:"username" db:"username"`
+	Email        string    `json:"email" db:"email"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         string    `json:"role" db:"role"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// User roles. Accounts are created as users; moderators are appointed with
+// the users command.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+)
+
+// Roles lists every valid role
+var Roles = []string{RoleUser, RoleModerator}
+
+// IsModerator reports whether the user may edit and delete other users' posts
+func (u User) IsModerator() bool {
+	return u.Role == RoleModerator
+}
+
+// RegisterRequest represents the structure for creating a new account
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// LoginRequest represents the structure for signing in with a username or email
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
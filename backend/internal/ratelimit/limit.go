@@ -0,0 +1,86 @@
+// Package ratelimit limits how often a user or client may call a route,
+// using the generic cell rate algorithm (GCRA) in Redis or in process
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period, in bursts of up to Requests. The zero
+// Limit allows everything.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses a limit written as requests/period, e.g. 5/1m or 100/h,
+// or off for no limit
+func ParseLimit(s string) (Limit, error) {
+	if s == "off" {
+		return Limit{}, nil
+	}
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("want requests/period, e.g. 5/1m, or off, got %q", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("want a positive number of requests, got %q", requests)
+	}
+	// Allow a bare unit, as in 100/h
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("want a positive period such as 30s, 1m or h, got %q", period)
+	}
+	if d/time.Duration(n) < time.Millisecond {
+		return Limit{}, fmt.Errorf("%q allows more than one request per millisecond", s)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Enabled reports whether the limit rejects anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0
+}
+
+// interval is the time it takes one request of the burst to replenish
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result is the decision on one request
+type Result struct {
+	Allowed bool
+	// Remaining is how many more requests would be allowed right now
+	Remaining int
+	// RetryAfter is how long until a denied request would be allowed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the whole burst is available again
+	ResetAfter time.Duration
+}
+
+// gcra decides on a request at now given the theoretical arrival time tat of
+// the key, the time at which its whole burst is available again, and returns
+// the key's new tat. The Redis script implements the same steps.
+func gcra(now, tat time.Time, limit Limit) (time.Time, Result) {
+	if tat.Before(now) {
+		tat = now
+	}
+	interval := limit.interval()
+	next := tat.Add(interval)
+	allowAt := next.Add(-limit.Period)
+	if now.Before(allowAt) {
+		return tat, Result{RetryAfter: allowAt.Sub(now), ResetAfter: tat.Sub(now)}
+	}
+	return next, Result{
+		Allowed:    true,
+		Remaining:  int(now.Add(limit.Period).Sub(next) / interval),
+		ResetAfter: next.Sub(now),
+	}
+}
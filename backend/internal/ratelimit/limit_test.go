@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{"5/1m", Limit{Requests: 5, Period: time.Minute}, false},
+		{"100/h", Limit{Requests: 100, Period: time.Hour}, false},
+		{"10/30s", Limit{Requests: 10, Period: 30 * time.Second}, false},
+		{"off", Limit{}, false},
+		{"", Limit{}, true},
+		{"5", Limit{}, true},
+		{"0/1m", Limit{}, true},
+		{"-1/1m", Limit{}, true},
+		{"five/1m", Limit{}, true},
+		{"5/", Limit{}, true},
+		{"5/soon", Limit{}, true},
+		{"5/0s", Limit{}, true},
+		{"2000/1s", Limit{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLimit(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGCRA(t *testing.T) {
+	// One request of the burst of 5 replenishes every 12s
+	limit := Limit{Requests: 5, Period: time.Minute}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name string
+		at   time.Duration // since start
+		want Result
+	}{
+		{"first", 0, Result{Allowed: true, Remaining: 4, ResetAfter: 12 * time.Second}},
+		{"second", 0, Result{Allowed: true, Remaining: 3, ResetAfter: 24 * time.Second}},
+		{"third", time.Second, Result{Allowed: true, Remaining: 2, ResetAfter: 35 * time.Second}},
+		{"fourth", time.Second, Result{Allowed: true, Remaining: 1, ResetAfter: 47 * time.Second}},
+		{"burst used up", time.Second, Result{Allowed: true, Remaining: 0, ResetAfter: 59 * time.Second}},
+		{"over the burst", 2 * time.Second, Result{RetryAfter: 10 * time.Second, ResetAfter: 58 * time.Second}},
+		{"denied requests cost nothing", 11 * time.Second, Result{RetryAfter: time.Second, ResetAfter: 49 * time.Second}},
+		{"one replenished", 12 * time.Second, Result{Allowed: true, Remaining: 0, ResetAfter: 60 * time.Second}},
+		{"whole burst replenished", 3 * time.Minute, Result{Allowed: true, Remaining: 4, ResetAfter: 12 * time.Second}},
+	}
+
+	var tat time.Time
+	for _, step := range steps {
+		var got Result
+		tat, got = gcra(start.Add(step.at), tat, limit)
+		if got != step.want {
+			t.Errorf("%s: gcra = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestMemoryAllow(t *testing.T) {
+	m := NewMemory()
+	limit := Limit{Requests: 2, Period: time.Hour}
+
+	for i, want := range []bool{true, true, false, false} {
+		if got := m.Allow("a", limit).Allowed; got != want {
+			t.Errorf("request %d to a: allowed = %v, want %v", i+1, got, want)
+		}
+	}
+	// Keys are limited independently
+	if !m.Allow("b", limit).Allowed {
+		t.Error("first request to b denied")
+	}
+}
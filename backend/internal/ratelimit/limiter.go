@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/cache"
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter enforces a limit per route and per user, or per client IP for
+// anonymous requests. It keeps its state in Redis, shared by every instance,
+// while the cache's Fallback is not degraded, and in process otherwise; a
+// Redis error degrades the Fallback like any other.
+type Limiter struct {
+	limits   map[string]Limit
+	redis    *Redis
+	fallback *cache.Fallback
+	local    *Memory
+	logger   *slog.Logger
+	metrics  *metrics.Metrics
+}
+
+// New creates a Limiter enforcing limits by route name. Without rdb and
+// fallback it keeps its state in process only. A nil logger logs to the
+// default logger, and rejections are counted in m unless it is nil.
+func New(limits map[string]Limit, rdb *redis.Client, fallback *cache.Fallback, logger *slog.Logger, m *metrics.Metrics) *Limiter {
+	l := &Limiter{limits: limits, local: NewMemory(), logger: logging.OrDefault(logger), metrics: m}
+	if rdb != nil && fallback != nil {
+		l.redis = NewRedis(rdb)
+		l.fallback = fallback
+	}
+	return l
+}
+
+// Middleware enforces the limit of route, setting the X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset headers, and rejects requests
+// over it with 429 and Retry-After. Routes without an enabled limit pass.
+func (l *Limiter) Middleware(route string) gin.HandlerFunc {
+	limit := l.limits[route]
+	if !limit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		key := "ratelimit:" + route + ":ip:" + c.ClientIP()
+		if userID, ok := auth.CurrentUserID(c); ok {
+			key = "ratelimit:" + route + ":user:" + strconv.FormatInt(userID, 10)
+		}
+
+		ctx := c.Request.Context()
+		res := l.allow(ctx, key, limit)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(res.ResetAfter)))
+		if !res.Allowed {
+			retryAfter := seconds(res.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			l.metrics.RateLimited(route)
+			logging.FromContext(ctx, l.logger).Debug("rate limited", "route", route, "retry_after", res.RetryAfter)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many requests, try again in " + strconv.Itoa(retryAfter) + "s",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow decides on a request in Redis, or in memory while Redis is down
+func (l *Limiter) allow(ctx context.Context, key string, limit Limit) Result {
+	if l.redis != nil && !l.fallback.Degraded() {
+		res, err := l.redis.Allow(ctx, key, limit)
+		if err == nil {
+			return res
+		}
+		// Calls cut short by their own context say nothing about Redis
+		if ctx.Err() == nil {
+			l.fallback.Degrade(err)
+		}
+	}
+	return l.local.Allow(key, limit)
+}
+
+// seconds rounds d up to whole seconds, as the headers carry
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
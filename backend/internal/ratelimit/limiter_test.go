@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestEngine serves POST /questions limited by l under the questions route
+func newTestEngine(l *Limiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/questions", l.Middleware("questions"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	engine.POST("/likes", l.Middleware("likes"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return engine
+}
+
+// post sends a POST to path from ip
+func post(engine *gin.Engine, path, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	limits := map[string]Limit{"questions": {Requests: 2, Period: time.Minute}}
+	engine := newTestEngine(New(limits, nil, nil, nil, nil))
+
+	tests := []struct {
+		ip        string
+		status    int
+		remaining string
+	}{
+		{"10.0.0.1", http.StatusCreated, "1"},
+		{"10.0.0.1", http.StatusCreated, "0"},
+		{"10.0.0.1", http.StatusTooManyRequests, "0"},
+		// Clients are limited separately
+		{"10.0.0.2", http.StatusCreated, "1"},
+	}
+	for i, tt := range tests {
+		rec := post(engine, "/questions", tt.ip)
+		if rec.Code != tt.status {
+			t.Errorf("request %d from %s: status = %d, want %d", i+1, tt.ip, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("request %d from %s: X-RateLimit-Remaining = %q, want %q", i+1, tt.ip, got, tt.remaining)
+		}
+		if got := rec.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d from %s: X-RateLimit-Limit = %q, want 2", i+1, tt.ip, got)
+		}
+		wantRetry := ""
+		if tt.status == http.StatusTooManyRequests {
+			wantRetry = "30"
+		}
+		if got := rec.Header().Get("Retry-After"); got != wantRetry {
+			t.Errorf("request %d from %s: Retry-After = %q, want %q", i+1, tt.ip, got, wantRetry)
+		}
+	}
+
+	// Routes without a limit pass untouched
+	for i := 0; i < 5; i++ {
+		if rec := post(engine, "/likes", "10.0.0.1"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("unlimited route: status = %d with headers %v", rec.Code, rec.Header())
+		}
+	}
+}
+
+func TestMiddlewareFallback(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	fallback := cache.NewFallback(cache.NewRedis(rdb), cache.NewMemory(0), logger)
+
+	limits := map[string]Limit{"questions": {Requests: 2, Period: time.Minute}}
+	engine := newTestEngine(New(limits, rdb, fallback, logger, nil))
+
+	// The first request is counted in Redis
+	if rec := post(engine, "/questions", "10.0.0.1"); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if !mr.Exists("ratelimit:questions:ip:10.0.0.1") {
+		t.Error("limit state not kept in Redis")
+	}
+
+	// With Redis down, requests are limited in process instead of failing
+	mr.Close()
+	for i, want := range []int{http.StatusCreated, http.StatusCreated, http.StatusTooManyRequests} {
+		if rec := post(engine, "/questions", "10.0.0.1"); rec.Code != want {
+			t.Errorf("request %d with Redis down: status = %d, want %d", i+1, rec.Code, want)
+		}
+	}
+	if !fallback.Degraded() {
+		t.Error("Redis error did not degrade the fallback")
+	}
+}
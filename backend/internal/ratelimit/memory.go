@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often a Memory drops the keys whose burst is full again
+const sweepInterval = time.Minute
+
+// Memory keeps the state of limits in process. Each server instance has its
+// own, so every instance allows the full limit.
+type Memory struct {
+	mu    sync.Mutex
+	tats  map[string]time.Time
+	swept time.Time
+}
+
+// NewMemory creates an empty Memory
+func NewMemory() *Memory {
+	return &Memory{tats: make(map[string]time.Time), swept: time.Now()}
+}
+
+// Allow decides on a request to key under limit
+func (m *Memory) Allow(key string, limit Limit) Result {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A key whose tat has passed holds nothing a missing key would not
+	if now.Sub(m.swept) >= sweepInterval {
+		for k, tat := range m.tats {
+			if tat.Before(now) {
+				delete(m.tats, k)
+			}
+		}
+		m.swept = now
+	}
+
+	tat, res := gcra(now, m.tats[key], limit)
+	m.tats[key] = tat
+	return res
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps the state of limits in Redis, shared by every server instance
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis creates a Redis limit store using rdb
+func NewRedis(rdb *redis.Client) *Redis {
+	return &Redis{client: rdb}
+}
+
+// gcraScript runs gcra on the tat stored under KEYS[1] for a limit with an
+// interval of ARGV[1] and a period of ARGV[2] microseconds, and returns
+// whether the request is allowed, the remaining requests and the retry and
+// reset delays in microseconds. It reads the time from Redis, so the
+// instances' clocks need not agree, and the key expires once the burst is
+// full again.
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - period
+if now < allow_at then
+	return {0, 0, allow_at - now, tat - now}
+end
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now + period - new_tat) / interval), 0, new_tat - now}
+`)
+
+// Allow decides on a request to key under limit
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := gcraScript.Run(ctx, r.client, []string{key},
+		limit.interval().Microseconds(), limit.Period.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply %v", values)
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
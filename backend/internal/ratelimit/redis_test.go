@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisAllow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	r := NewRedis(rdb)
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	// The script runs the same steps as gcra, on the Redis clock; only a few
+	// microseconds pass between the requests
+	var tat time.Time
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		got, err := r.Allow(ctx, "ratelimit:test", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want Result
+		tat, want = gcra(now, tat, limit)
+		if got.Allowed != want.Allowed || got.Remaining != want.Remaining ||
+			!near(got.RetryAfter, want.RetryAfter) || !near(got.ResetAfter, want.ResetAfter) {
+			t.Errorf("request %d: Allow = %+v, want about %+v", i+1, got, want)
+		}
+	}
+
+	// The key expires once the whole burst is available again
+	if ttl := mr.TTL("ratelimit:test"); !near(ttl, time.Minute) {
+		t.Errorf("TTL = %v, want about 1m", ttl)
+	}
+	if other, err := r.Allow(ctx, "ratelimit:other", limit); err != nil || !other.Allowed || other.Remaining != 2 {
+		t.Errorf("first request to another key: Allow = %+v, %v", other, err)
+	}
+
+	mr.Close()
+	if _, err := r.Allow(ctx, "ratelimit:test", limit); err == nil {
+		t.Error("Allow succeeded with Redis down")
+	}
+}
+
+// near reports whether two delays differ by less than 100ms
+func near(a, b time.Duration) bool {
+	d := a - b
+	return d > -100*time.Millisecond && d < 100*time.Millisecond
+}
@@ -1,47 +1,129 @@
 package router
 
 import (
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/questions/backend/internal/api"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/health"
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/metrics"
+	"github.com/questions/backend/internal/ratelimit"
+	"github.com/questions/backend/internal/tracing"
 )
 
-// SetupRouter configures the application's routes
-func SetupRouter() *gin.Engine {
+// SetupRouter configures the application's routes. Requests are logged to
+// logger, tagged with their request ID, and recorded in m unless it is nil.
+// checker answers the health probes, and limiter limits write routes.
+// Client IPs are read from X-Forwarded-For only when the request comes from
+// one of trustedProxies.
+func SetupRouter(h *api.Handler, tokens *auth.TokenManager, logger *slog.Logger, m *metrics.Metrics, checker *health.Checker,
+	limiter *ratelimit.Limiter, trustedProxies []string) (*gin.Engine, error) {
 	// Set Gin mode based on environment
 	// gin.SetMode(gin.ReleaseMode) // Uncomment for production
 
-	r := gin.Default()
+	r := gin.New()
+
+	// Rate limits, like deduplication and view counts key on the client IP,
+	// so a client must not be able to pick it with a forged header
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	// Trace requests, tag them with an ID for their log lines, log them once
+	// served and turn panics into 500s
+	r.Use(tracing.Middleware(), logging.RequestID(logger), logging.AccessLog(), gin.Recovery())
+
+	// Record request durations per route and serve them to Prometheus
+	if m != nil {
+		r.Use(m.Middleware())
+		r.GET("/metrics", gin.WrapH(m.Handler()))
+	}
+
+	// Liveness, readiness and build status for PM2, Docker and load balancers
+	r.GET("/healthz", checker.Live)
+	r.GET("/readyz", checker.Ready)
+	r.GET("/status", checker.Status)
 
 	// Configure CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3001", "https://web3ite.tech", "https://www.web3ite.tech"}, // Frontend URLs
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", logging.RequestIDHeader, "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Populate the current user from the Authorization header
+	r.Use(auth.Middleware(tokens))
+
+	// Limits of write routes, per user or client IP
+	limitQuestions := limiter.Middleware("questions")
+	limitAnswers := limiter.Middleware("answers")
+	limitComments := limiter.Middleware("comments")
+	limitLikes := limiter.Middleware("likes")
+
 	// API routes
 	v1 := r.Group("/api/v1")
 	{
+		// Auth routes
+		authRoutes := v1.Group("/auth")
+		{
+			authRoutes.POST("/register", h.Register)
+			authRoutes.POST("/login", h.Login)
+			authRoutes.GET("/me", auth.RequireUser(), h.Me)
+		}
+
+		// Cache hit and miss counts
+		v1.GET("/cache/stats", h.CacheStats)
+
+		// Tag directory
+		tags := v1.Group("/tags")
+		{
+			tags.GET("", h.ListTags)
+			tags.GET("/autocomplete", h.AutocompleteTags)
+			tags.GET("/:name", h.GetTag)
+			tags.PUT("/:name", auth.RequireUser(), h.RequireModerator(), h.UpdateTag)
+		}
+
 		// Questions routes
 		questions := v1.Group("/questions")
 		{
-			questions.GET("", api.GetQuestions)
-			questions.GET("/:id", api.GetQuestion)
-			questions.POST("", api.CreateQuestion)
+			questions.GET("", h.GetQuestions)
+			questions.GET("/:id", h.GetQuestion)
+			questions.POST("", limitQuestions, h.CreateQuestion)
+			questions.PUT("/:id", auth.RequireUser(), h.UpdateQuestion)
+			questions.PATCH("/:id", auth.RequireUser(), h.UpdateQuestion)
+			questions.DELETE("/:id", auth.RequireUser(), h.DeleteQuestion)
+
+			// Revisions
+			questions.GET("/:id/revisions", h.ListRevisions)
+			questions.GET("/:id/revisions/:rev/diff", h.RevisionDiff)
+			questions.POST("/:id/revisions/:rev/rollback", auth.RequireUser(), h.RollbackRevision)
+
+			// Daily analytics for the question's author
+			questions.GET("/:id/stats", auth.RequireUser(), h.GetQuestionStats)
 
 			// Comments
-			questions.POST("/:id/comments", api.AddComment)
+			questions.POST("/:id/comments", limitComments, h.AddComment)
+
+			// Answers
+			questions.GET("/:id/answers", h.ListAnswers)
+			questions.POST("/:id/answers", limitAnswers, h.CreateAnswer)
+			questions.PUT("/:id/answers/:answerId", auth.RequireUser(), h.UpdateAnswer)
+			questions.POST("/:id/answers/:answerId/comments", limitComments, h.AddAnswerComment)
+			questions.POST("/:id/answers/:answerId/accept", auth.RequireUser(), h.AcceptAnswer)
+			questions.DELETE("/:id/answers/:answerId/accept", auth.RequireUser(), h.UnacceptAnswer)
 
 			// Likes
-			questions.POST("/:id/like", api.LikeQuestion)
+			questions.POST("/:id/like", limitLikes, h.LikeQuestion)
 		}
 	}
 
-	return r
+	return r, nil
 }
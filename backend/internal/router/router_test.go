@@ -0,0 +1,63 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/questions/backend/internal/api"
+	"github.com/questions/backend/internal/auth"
+	"github.com/questions/backend/internal/health"
+	"github.com/questions/backend/internal/ratelimit"
+	"github.com/questions/backend/internal/store"
+)
+
+func TestForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		peer    string
+		limited bool // whether the third request with a new X-Forwarded-For is rejected
+	}{
+		{"no trusted proxies", nil, "203.0.113.5", true},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "203.0.113.5", true},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.1.2.3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			tokens := auth.NewTokenManager("test-secret", time.Hour)
+			h := api.NewHandler(store.NewMemoryStore(), tokens, nil, nil, logger, nil)
+			limiter := ratelimit.New(map[string]ratelimit.Limit{"questions": {Requests: 2, Period: time.Minute}}, nil, nil, logger, nil)
+			r, err := SetupRouter(h, tokens, logger, nil, health.New(health.Build{}, time.Second), limiter, tt.proxies)
+			if err != nil {
+				t.Fatalf("SetupRouter: %v", err)
+			}
+
+			// Every request claims to be forwarded for a different client
+			var codes []int
+			for i := 1; i <= 3; i++ {
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/questions", nil)
+				req.RemoteAddr = tt.peer + ":40000"
+				req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+				codes = append(codes, rec.Code)
+			}
+			if limited := codes[2] == http.StatusTooManyRequests; limited != tt.limited {
+				t.Errorf("statuses = %v; third request limited = %v, want %v", codes, limited, tt.limited)
+			}
+		})
+	}
+
+	if _, err := SetupRouter(nil, nil, nil, nil, nil, nil, []string{"proxy.local"}); err == nil {
+		t.Error("SetupRouter accepted a proxy that is not an IP or CIDR")
+	}
+}
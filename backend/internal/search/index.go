@@ -0,0 +1,312 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights: a term in the title counts three times as much as in the
+// body, mirroring the MySQL full-text ranking
+const (
+	titleWeight   = 3
+	tagWeight     = 2
+	contentWeight = 1
+	commentWeight = 1
+)
+
+// Document is the searchable text of a question
+type Document struct {
+	ID       int64
+	Title    string
+	Content  string
+	Tags     []string
+	Comments []string
+}
+
+// Hit is a document matching a query with its BM25 score
+type Hit struct {
+	ID    int64
+	Score float64
+}
+
+// indexedDoc holds a document's weighted term frequencies and length
+type indexedDoc struct {
+	freqs  map[string]float64
+	length float64
+}
+
+// Index is an in-process inverted index over question documents ranked with
+// BM25. It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[int64]*indexedDoc
+	postings    map[string]map[int64]struct{} // term -> IDs of documents containing it
+	totalLength float64
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[int64]*indexedDoc),
+		postings: make(map[string]map[int64]struct{}),
+	}
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Terms returns the number of distinct terms in the index
+func (ix *Index) Terms() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.postings)
+}
+
+// Put indexes a document, replacing any earlier version with the same ID
+func (ix *Index) Put(doc Document) {
+	entry := &indexedDoc{freqs: make(map[string]float64)}
+	entry.add(doc.Title, titleWeight)
+	entry.add(doc.Content, contentWeight)
+	for _, tag := range doc.Tags {
+		entry.add(tag, tagWeight)
+	}
+	for _, comment := range doc.Comments {
+		entry.add(comment, commentWeight)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.removeLocked(doc.ID)
+	ix.insertLocked(doc.ID, entry)
+}
+
+// AddComment adds a comment's text to an indexed document. Unknown IDs are ignored.
+func (ix *Index) AddComment(id int64, comment string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	entry, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	before := entry.length
+	for _, term := range entry.add(comment, commentWeight) {
+		ix.post(term, id)
+	}
+	ix.totalLength += entry.length - before
+}
+
+// Remove drops a document from the index
+func (ix *Index) Remove(id int64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.removeLocked(id)
+}
+
+// Replace swaps the whole index content for the given documents
+func (ix *Index) Replace(docs []Document) {
+	fresh := NewIndex()
+	for _, doc := range docs {
+		fresh.Put(doc)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.docs = fresh.docs
+	ix.postings = fresh.postings
+	ix.totalLength = fresh.totalLength
+}
+
+// Search returns the documents matching the query, best first, at most limit
+// of them when limit is positive. Quoted phrases match documents containing
+// all of their words.
+func (ix *Index) Search(query string, boolean bool, limit int) []Hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var required, optional, excluded [][][]string
+	for _, term := range ParseQuery(query, boolean) {
+		groups := ix.expand(term)
+		if len(groups) == 0 {
+			continue
+		}
+		switch {
+		case term.Excluded:
+			excluded = append(excluded, groups)
+		case term.Required:
+			required = append(required, groups)
+		default:
+			optional = append(optional, groups)
+		}
+	}
+
+	var candidates map[int64]struct{}
+	if len(required) > 0 {
+		candidates = ix.matching(required[0])
+		for _, groups := range required[1:] {
+			matches := ix.matching(groups)
+			for id := range candidates {
+				if _, ok := matches[id]; !ok {
+					delete(candidates, id)
+				}
+			}
+		}
+	} else {
+		candidates = make(map[int64]struct{})
+		for _, groups := range optional {
+			for id := range ix.matching(groups) {
+				candidates[id] = struct{}{}
+			}
+		}
+	}
+	for _, groups := range excluded {
+		for id := range ix.matching(groups) {
+			delete(candidates, id)
+		}
+	}
+
+	hits := make([]Hit, 0, len(candidates))
+	for id := range candidates {
+		var score float64
+		for _, groups := range append(required, optional...) {
+			for _, group := range groups {
+				for _, term := range group {
+					score += ix.bm25(term, id)
+				}
+			}
+		}
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// expand turns a query term into groups of index terms. A document matches
+// the term when it contains a term of every group. Stop words expand to nothing.
+func (ix *Index) expand(term Term) [][]string {
+	if term.Phrase {
+		var groups [][]string
+		for _, t := range Tokenize(term.Text) {
+			groups = append(groups, []string{t})
+		}
+		return groups
+	}
+
+	if term.Prefix {
+		// Index terms are stemmed, so "caching*" has to match "cach" as well
+		stem := Stem(term.Text)
+		var group []string
+		for t := range ix.postings {
+			if strings.HasPrefix(t, term.Text) || strings.HasPrefix(t, stem) {
+				group = append(group, t)
+			}
+		}
+		if len(group) == 0 {
+			// Keep an unmatched group so a required prefix still filters everything out
+			group = []string{term.Text}
+		}
+		return [][]string{group}
+	}
+
+	if stopWords[term.Text] {
+		return nil
+	}
+	return [][]string{{Stem(term.Text)}}
+}
+
+// matching returns the IDs of documents containing a term of every group
+func (ix *Index) matching(groups [][]string) map[int64]struct{} {
+	var ids map[int64]struct{}
+	for _, group := range groups {
+		inGroup := make(map[int64]struct{})
+		for _, term := range group {
+			for id := range ix.postings[term] {
+				if _, ok := ids[id]; ids == nil || ok {
+					inGroup[id] = struct{}{}
+				}
+			}
+		}
+		ids = inGroup
+	}
+	return ids
+}
+
+// bm25 scores one term for one document
+func (ix *Index) bm25(term string, id int64) float64 {
+	doc := ix.docs[id]
+	tf := doc.freqs[term]
+	if tf == 0 {
+		return 0
+	}
+
+	n := float64(len(ix.docs))
+	df := float64(len(ix.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+	avgLength := ix.totalLength / n
+
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*doc.length/avgLength))
+}
+
+// add tokenizes text into the document with the given field weight and
+// returns the terms it added
+func (d *indexedDoc) add(text string, weight float64) []string {
+	terms := Tokenize(text)
+	for _, term := range terms {
+		d.freqs[term] += weight
+	}
+	d.length += weight * float64(len(terms))
+	return terms
+}
+
+func (ix *Index) post(term string, id int64) {
+	ids, ok := ix.postings[term]
+	if !ok {
+		ids = make(map[int64]struct{})
+		ix.postings[term] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (ix *Index) insertLocked(id int64, entry *indexedDoc) {
+	ix.docs[id] = entry
+	ix.totalLength += entry.length
+	for term := range entry.freqs {
+		ix.post(term, id)
+	}
+}
+
+func (ix *Index) removeLocked(id int64) {
+	entry, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for term := range entry.freqs {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.totalLength -= entry.length
+	delete(ix.docs, id)
+}
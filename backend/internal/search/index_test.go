@@ -0,0 +1,129 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestIndex indexes a few questions about caching and databases
+func newTestIndex() *Index {
+	ix := NewIndex()
+	ix.Replace([]Document{
+		{ID: 1, Title: "Caching query results in Redis", Content: "Which eviction policy should a cache use?", Tags: []string{"redis", "caching"}},
+		{ID: 2, Title: "MySQL connection pool exhausted", Content: "The pool runs out of connections under load.", Tags: []string{"mysql"}},
+		{ID: 3, Title: "Slow queries", Content: "Would a Redis cache help my MySQL queries?", Tags: []string{"mysql", "performance"}},
+		{ID: 4, Title: "Goroutine leak", Content: "A connection is never closed in the pool worker.", Tags: []string{"go"}},
+	})
+	return ix
+}
+
+// hitIDs returns the IDs of hits in order
+func hitIDs(hits []Hit) []int64 {
+	ids := []int64{}
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := newTestIndex()
+
+	tests := []struct {
+		name    string
+		query   string
+		boolean bool
+		limit   int
+		want    []int64
+	}{
+		// A title match outranks a content match
+		{"ranked by field", "redis", false, 0, []int64{1, 3}},
+		{"stemmed", "cached", false, 0, []int64{1, 3}},
+		{"any word matches", "goroutine eviction", false, 0, []int64{4, 1}},
+		{"stop words only", "how is the", false, 0, []int64{}},
+		{"unknown word", "kubernetes", false, 0, []int64{}},
+		{"limit", "redis", false, 1, []int64{1}},
+		{"required", "+mysql +redis", true, 0, []int64{3}},
+		{"excluded", "pool -goroutine", true, 0, []int64{2}},
+		{"phrase", `"connection pool"`, true, 0, []int64{2, 4}},
+		{"prefix", "perf*", true, 0, []int64{3}},
+		{"stemmed prefix", "+caching*", true, 0, []int64{1, 3}},
+		{"unmatched required prefix", "+kube* redis", true, 0, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hitIDs(ix.Search(tt.query, tt.boolean, tt.limit)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexScores(t *testing.T) {
+	ix := newTestIndex()
+
+	hits := ix.Search("pool", false, 0)
+	if len(hits) != 2 || hits[0].ID != 2 {
+		t.Fatalf("Search(pool) = %+v, want question 2 first", hits)
+	}
+	// Question 2 has "pool" in its title and content, question 4 only in content
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("scores = %v, %v, want the title match higher", hits[0].Score, hits[1].Score)
+	}
+
+	// A rare term weighs more than a common one
+	rare := ix.Search("goroutine", false, 0)
+	common := ix.Search("connection", false, 0)
+	if len(rare) != 1 || len(common) != 2 {
+		t.Fatalf("Search(goroutine) = %+v, Search(connection) = %+v", rare, common)
+	}
+	var commonScore float64
+	for _, hit := range common {
+		if hit.ID == 4 {
+			commonScore = hit.Score
+		}
+	}
+	if rare[0].Score <= commonScore {
+		t.Errorf("goroutine scored %v, connection %v in the same document; want the rarer term higher", rare[0].Score, commonScore)
+	}
+}
+
+func TestIndexUpdates(t *testing.T) {
+	ix := newTestIndex()
+	if ix.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", ix.Len())
+	}
+
+	// Put replaces the earlier version, dropping its terms
+	ix.Put(Document{ID: 4, Title: "Channel deadlock", Content: "All goroutines are asleep."})
+	if got := hitIDs(ix.Search("pool", false, 0)); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("after Put, Search(pool) = %v, want [2]", got)
+	}
+	if got := hitIDs(ix.Search("deadlock", false, 0)); !reflect.DeepEqual(got, []int64{4}) {
+		t.Errorf("after Put, Search(deadlock) = %v, want [4]", got)
+	}
+
+	// Comments are searchable; unknown documents are ignored
+	ix.AddComment(2, "Raise max_open_conns and check for unreleased rows")
+	ix.AddComment(99, "Unreleased")
+	if got := hitIDs(ix.Search("unreleased", false, 0)); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("after AddComment, Search(unreleased) = %v, want [2]", got)
+	}
+
+	ix.Remove(1)
+	if got := hitIDs(ix.Search("redis", false, 0)); !reflect.DeepEqual(got, []int64{3}) {
+		t.Errorf("after Remove, Search(redis) = %v, want [3]", got)
+	}
+	if ix.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", ix.Len())
+	}
+
+	// Replace drops every term of the old content
+	ix.Replace([]Document{{ID: 10, Title: "Fresh start"}})
+	if ix.Len() != 1 || ix.Terms() != 2 {
+		t.Errorf("after Replace, Len() = %d and Terms() = %d, want 1 and 2", ix.Len(), ix.Terms())
+	}
+	if got := hitIDs(ix.Search("mysql", false, 0)); len(got) != 0 {
+		t.Errorf("after Replace, Search(mysql) = %v, want none", got)
+	}
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Term is one word or quoted phrase of a search query, lowercased but not stemmed
+type Term struct {
+	Text     string
+	Phrase   bool // quoted; Text holds its words separated by spaces
+	Prefix   bool // trailing *; matches words starting with Text
+	Required bool // leading + in boolean mode
+	Excluded bool // leading - in boolean mode
+}
+
+// ParseQuery splits a search query into terms. In boolean mode it honors
+// +required, -excluded, "quoted phrases" and prefix* operators; otherwise
+// every word is an optional term.
+func ParseQuery(query string, boolean bool) []Term {
+	var terms []Term
+
+	if !boolean {
+		for _, word := range Words(query) {
+			terms = append(terms, Term{Text: word})
+		}
+		return terms
+	}
+
+	query = strings.ToLower(query)
+	for len(query) > 0 {
+		query = strings.TrimLeftFunc(query, unicode.IsSpace)
+		if query == "" {
+			break
+		}
+
+		var term Term
+		switch query[0] {
+		case '+':
+			term.Required = true
+			query = query[1:]
+		case '-':
+			term.Excluded = true
+			query = query[1:]
+		}
+
+		if strings.HasPrefix(query, `"`) {
+			end := strings.Index(query[1:], `"`)
+			if end < 0 {
+				end = len(query) - 1
+			}
+			term.Text = strings.Join(Words(query[1:1+end]), " ")
+			term.Phrase = true
+			query = query[min(len(query), end+2):]
+		} else {
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				end = len(query)
+			}
+			word := query[:end]
+			query = query[end:]
+			term.Prefix = strings.HasSuffix(word, "*")
+			if words := Words(word); len(words) > 0 {
+				term.Text = words[0]
+			}
+		}
+
+		if term.Text != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
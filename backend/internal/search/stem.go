@@ -0,0 +1,231 @@
+package search
+
+import "strings"
+
+// Stem reduces an English word to its stem with the Porter algorithm, so that
+// "caching", "cached" and "cache" index as the same term. Words that are not
+// plain lowercase ASCII, or that are shorter than three letters, are returned unchanged.
+func Stem(word string) string {
+	if len(word) < 3 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+type stemmer struct {
+	b []byte
+}
+
+// consonant reports whether b[i] is a consonant. Y is a consonant at the
+// start of a word and after a vowel.
+func (s *stemmer) consonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.consonant(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in b[:n]
+func (s *stemmer) measure(n int) int {
+	m := 0
+	i := 0
+	for i < n && s.consonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !s.consonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		m++
+		for i < n && s.consonant(i) {
+			i++
+		}
+	}
+	return m
+}
+
+// hasVowel reports whether b[:n] contains a vowel
+func (s *stemmer) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !s.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant reports whether b[:n] ends with the same consonant twice
+func (s *stemmer) doubleConsonant(n int) bool {
+	return n >= 2 && s.b[n-1] == s.b[n-2] && s.consonant(n-1)
+}
+
+// cvc reports whether b[:n] ends consonant-vowel-consonant with the last
+// consonant not w, x or y, as in "hop" but not "snow"
+func (s *stemmer) cvc(n int) bool {
+	if n < 3 || !s.consonant(n-1) || s.consonant(n-2) || !s.consonant(n-3) {
+		return false
+	}
+	switch s.b[n-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// replace swaps suffix for replacement
+func (s *stemmer) replace(suffix, replacement string) {
+	s.b = append(s.b[:len(s.b)-len(suffix)], replacement...)
+}
+
+// replaceLongest finds the longest rule suffix the word ends with and replaces
+// it when the remaining stem has a measure above minMeasure
+func (s *stemmer) replaceLongest(rules [][2]string, minMeasure int) {
+	best := -1
+	for i, rule := range rules {
+		if s.hasSuffix(rule[0]) && (best < 0 || len(rule[0]) > len(rules[best][0])) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return
+	}
+	if s.measure(len(s.b)-len(rules[best][0])) > minMeasure {
+		s.replace(rules[best][0], rules[best][1])
+	}
+}
+
+func (s *stemmer) step1a() {
+	switch {
+	case s.hasSuffix("sses"):
+		s.replace("sses", "ss")
+	case s.hasSuffix("ies"):
+		s.replace("ies", "i")
+	case s.hasSuffix("ss"):
+	case s.hasSuffix("s"):
+		s.replace("s", "")
+	}
+}
+
+func (s *stemmer) step1b() {
+	if s.hasSuffix("eed") {
+		if s.measure(len(s.b)-3) > 0 {
+			s.replace("eed", "ee")
+		}
+		return
+	}
+
+	var suffix string
+	switch {
+	case s.hasSuffix("ed"):
+		suffix = "ed"
+	case s.hasSuffix("ing"):
+		suffix = "ing"
+	default:
+		return
+	}
+	if !s.hasVowel(len(s.b) - len(suffix)) {
+		return
+	}
+	s.replace(suffix, "")
+
+	n := len(s.b)
+	switch {
+	case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+		s.b = append(s.b, 'e')
+	case s.doubleConsonant(n) && s.b[n-1] != 'l' && s.b[n-1] != 's' && s.b[n-1] != 'z':
+		s.b = s.b[:n-1]
+	case s.measure(n) == 1 && s.cvc(n):
+		s.b = append(s.b, 'e')
+	}
+}
+
+func (s *stemmer) step1c() {
+	if s.hasSuffix("y") && s.hasVowel(len(s.b)-1) {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+var step2Rules = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+func (s *stemmer) step2() {
+	s.replaceLongest(step2Rules, 0)
+}
+
+var step3Rules = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+func (s *stemmer) step3() {
+	s.replaceLongest(step3Rules, 0)
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+func (s *stemmer) step4() {
+	suffix := ""
+	for _, candidate := range step4Suffixes {
+		if s.hasSuffix(candidate) && len(candidate) > len(suffix) {
+			suffix = candidate
+		}
+	}
+	if suffix == "" {
+		return
+	}
+
+	n := len(s.b) - len(suffix)
+	if suffix == "ion" && (n == 0 || (s.b[n-1] != 's' && s.b[n-1] != 't')) {
+		return
+	}
+	if s.measure(n) > 1 {
+		s.b = s.b[:n]
+	}
+}
+
+func (s *stemmer) step5() {
+	if s.hasSuffix("e") {
+		n := len(s.b) - 1
+		if m := s.measure(n); m > 1 || (m == 1 && !s.cvc(n)) {
+			s.b = s.b[:n]
+		}
+	}
+
+	n := len(s.b)
+	if s.measure(n) > 1 && s.doubleConsonant(n) && s.b[n-1] == 'l' {
+		s.b = s.b[:n-1]
+	}
+}
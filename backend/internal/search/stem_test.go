@@ -0,0 +1,52 @@
+package search
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		// Step 1a: plurals
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"caress", "caress"},
+		{"cats", "cat"},
+		// Step 1b: -ed and -ing
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"hopping", "hop"},
+		{"falling", "fall"},
+		{"filing", "file"},
+		// Step 1c: y to i
+		{"happy", "happi"},
+		{"sky", "sky"},
+		// Steps 2 to 4: derivational suffixes
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"hopeful", "hope"},
+		{"goodness", "good"},
+		{"adjustment", "adjust"},
+		{"generalizations", "gener"},
+		// Step 5: final e and double l
+		{"probate", "probat"},
+		{"rate", "rate"},
+		{"controll", "control"},
+		// Forms of one word share a stem
+		{"caching", "cach"},
+		{"cached", "cach"},
+		{"cache", "cach"},
+		// Left unchanged
+		{"go", "go"},
+		{"mysql8", "mysql8"},
+		{"café", "café"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no meaning for ranking
+var stopWords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "as": true,
+	"at": true, "be": true, "but": true, "by": true, "can": true, "do": true,
+	"does": true, "for": true, "from": true, "had": true, "has": true, "have": true,
+	"how": true, "i": true, "if": true, "in": true, "into": true, "is": true,
+	"it": true, "its": true, "me": true, "my": true, "no": true, "not": true,
+	"of": true, "on": true, "or": true, "so": true, "such": true, "that": true,
+	"the": true, "their": true, "then": true, "there": true, "these": true,
+	"they": true, "this": true, "to": true, "was": true, "we": true, "what": true,
+	"when": true, "where": true, "which": true, "while": true, "who": true,
+	"why": true, "will": true, "with": true, "would": true, "you": true, "your": true,
+}
+
+// Words lowercases text and splits it into runs of letters and digits
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Tokenize turns text into index terms: lowercased words without stop words, stemmed
+func Tokenize(text string) []string {
+	words := Words(text)
+	terms := words[:0]
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		terms = append(terms, Stem(word))
+	}
+	return terms
+}
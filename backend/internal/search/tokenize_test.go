@@ -0,0 +1,72 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Hello, World!", []string{"hello", "world"}},
+		{"go-redis v9 (MySQL 8.0)", []string{"go", "redis", "v9", "mysql", "8", "0"}},
+		{"  Größe\tändern ", []string{"größe", "ändern"}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"How do I cache the query results?", []string{"cach", "queri", "result"}},
+		{"The and of it", []string{}},
+		{"Caching cached CACHES", []string{"cach", "cach", "cach"}},
+	}
+	for _, tt := range tests {
+		got := Tokenize(tt.text)
+		if got == nil {
+			got = []string{}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		boolean bool
+		want    []Term
+	}{
+		{"natural", "Redis +cache -eviction", false, []Term{{Text: "redis"}, {Text: "cache"}, {Text: "eviction"}}},
+		{"natural ignores quotes", `"connection pool"`, false, []Term{{Text: "connection"}, {Text: "pool"}}},
+		{"required and excluded", "+Redis -memcached cache", true, []Term{
+			{Text: "redis", Required: true}, {Text: "memcached", Excluded: true}, {Text: "cache"},
+		}},
+		{"phrase", `+"Connection  Pool" timeout`, true, []Term{
+			{Text: "connection pool", Phrase: true, Required: true}, {Text: "timeout"},
+		}},
+		{"unterminated phrase", `"connection pool`, true, []Term{{Text: "connection pool", Phrase: true}}},
+		{"prefix", "cach* -test*", true, []Term{{Text: "cach", Prefix: true}, {Text: "test", Prefix: true, Excluded: true}}},
+		{"lone operators are dropped", `+ - "" *`, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseQuery(tt.query, tt.boolean); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery(%q, %v) = %+v, want %+v", tt.query, tt.boolean, got, tt.want)
+			}
+		})
+	}
+}
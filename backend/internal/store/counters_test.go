@@ -0,0 +1,85 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/questions/backend/internal/db"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+// testStores returns the stores to test by name: always a MemoryStore, and a
+// MySQLStore on the migrated database TEST_MYSQL_DSN names when it is set,
+// e.g. user:password@tcp(localhost:3306)/questions_test?parseTime=true. The
+// MySQL tests write to that database.
+func testStores(t *testing.T) map[string]store.Store {
+	t.Helper()
+	stores := map[string]store.Store{"memory": store.NewMemoryStore()}
+
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		return stores
+	}
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("opening TEST_MYSQL_DSN: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if _, err := db.MigrateUp(context.Background(), conn); err != nil {
+		t.Fatalf("migrating TEST_MYSQL_DSN: %v", err)
+	}
+	stores["mysql"] = store.NewMySQLStore(conn)
+	return stores
+}
+
+func TestCountersKeepUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	for name, st := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			questionID, err := st.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Counted question", Content: "Not edited"}, nil)
+			if err != nil {
+				t.Fatalf("creating question: %v", err)
+			}
+			t.Cleanup(func() { st.DeleteQuestion(ctx, questionID) })
+			created, err := st.GetQuestion(ctx, questionID)
+			if err != nil {
+				t.Fatalf("getting question: %v", err)
+			}
+
+			steps := []struct {
+				name string
+				run  func() error
+			}{
+				{"like", func() error { _, _, err := st.ToggleLike(ctx, questionID, nil, "192.0.2.1"); return err }},
+				{"unlike", func() error { _, _, err := st.ToggleLike(ctx, questionID, nil, "192.0.2.1"); return err }},
+				{"view", func() error { return st.IncrementViewCount(ctx, questionID) }},
+				{"buffered views", func() error { return st.AddViewCounts(ctx, map[int64]int{questionID: 3}) }},
+				{"answer", func() error {
+					_, err := st.CreateAnswer(ctx, models.Answer{QuestionID: questionID, Content: "An answer"})
+					return err
+				}},
+			}
+			for _, step := range steps {
+				// MySQL keeps updated_at in whole seconds
+				if name == "mysql" {
+					time.Sleep(time.Second)
+				}
+				if err := step.run(); err != nil {
+					t.Fatalf("%s: %v", step.name, err)
+				}
+				q, err := st.GetQuestion(ctx, questionID)
+				if err != nil {
+					t.Fatalf("getting question after %s: %v", step.name, err)
+				}
+				if !q.UpdatedAt.Equal(created.UpdatedAt) {
+					t.Errorf("%s changed updated_at from %v to %v", step.name, created.UpdatedAt, q.UpdatedAt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/questions/backend/internal/logging"
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/search"
+)
+
+// maxSearchHits caps how many ranked questions a relevance-ordered search
+// passes to the wrapped store, bounding the size of the ID filter it has to
+// apply. Other orders and counts get every match, since the best-ranked hits
+// are not the newest or most liked ones.
+const maxSearchHits = 1000
+
+// rebuildPageSize is how many questions Rebuild reads per query
+const rebuildPageSize = 500
+
+// IndexedStore wraps a Store and answers search queries from an in-process
+// inverted index instead of the wrapped store's own search. Writes that
+// change searchable text update the index as they happen.
+type IndexedStore struct {
+	Store
+	index  *search.Index
+	logger *slog.Logger
+}
+
+// NewIndexedStore wraps s with the given index. Call Rebuild to fill the
+// index with the questions already in s. Failures to update the index are
+// logged to the logger of the write's context, or else to logger.
+func NewIndexedStore(s Store, index *search.Index, logger *slog.Logger) *IndexedStore {
+	return &IndexedStore{Store: s, index: index, logger: logging.OrDefault(logger)}
+}
+
+// Index returns the search index backing the store
+func (s *IndexedStore) Index() *search.Index {
+	return s.index
+}
+
+// Rebuild re-indexes every question of the wrapped store and returns how many
+// were indexed. Writes made while it runs may be missing until the next rebuild.
+func (s *IndexedStore) Rebuild(ctx context.Context) (int, error) {
+	var docs []search.Document
+	params := ListQuestionsParams{Sort: "created_at", Order: "asc", Limit: rebuildPageSize}
+	for {
+		page, err := s.Store.ListQuestions(ctx, params)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list questions: %w", err)
+		}
+		for _, q := range page {
+			doc, err := s.document(ctx, q)
+			if err != nil {
+				return 0, err
+			}
+			docs = append(docs, doc)
+		}
+		if len(page) < params.Limit {
+			break
+		}
+		last := CursorOf(page[len(page)-1])
+		params.After = &last
+	}
+
+	s.index.Replace(docs)
+	return len(docs), nil
+}
+
+// ListQuestions implements QuestionStore
+func (s *IndexedStore) ListQuestions(ctx context.Context, params ListQuestionsParams) ([]models.Question, error) {
+	limit := 0
+	if params.sortField() == SortRelevance {
+		limit = maxSearchHits
+	}
+	return s.Store.ListQuestions(ctx, s.withHits(params, limit))
+}
+
+// CountQuestions implements QuestionStore
+func (s *IndexedStore) CountQuestions(ctx context.Context, params ListQuestionsParams) (int, error) {
+	return s.Store.CountQuestions(ctx, s.withHits(params, 0))
+}
+
+// CreateQuestion implements QuestionStore
+func (s *IndexedStore) CreateQuestion(ctx context.Context, req models.QuestionCreateRequest, authorID *int64) (int64, error) {
+	id, err := s.Store.CreateQuestion(ctx, req, authorID)
+	if err != nil {
+		return 0, err
+	}
+	s.reindex(ctx, id)
+	return id, nil
+}
+
+// UpdateQuestion implements QuestionStore
+func (s *IndexedStore) UpdateQuestion(ctx context.Context, id int64, update QuestionUpdate) error {
+	if err := s.Store.UpdateQuestion(ctx, id, update); err != nil {
+		return err
+	}
+	s.reindex(ctx, id)
+	return nil
+}
+
+// DeleteQuestion implements QuestionStore
+func (s *IndexedStore) DeleteQuestion(ctx context.Context, id int64) error {
+	if err := s.Store.DeleteQuestion(ctx, id); err != nil {
+		return err
+	}
+	s.index.Remove(id)
+	return nil
+}
+
+// AddComment implements CommentStore
+func (s *IndexedStore) AddComment(ctx context.Context, comment models.Comment) (int64, error) {
+	id, err := s.Store.AddComment(ctx, comment)
+	if err != nil {
+		return 0, err
+	}
+	s.index.AddComment(comment.QuestionID, comment.Content)
+	return id, nil
+}
+
+// MergeTags implements TagStore
+func (s *IndexedStore) MergeTags(ctx context.Context, alias, canonical string) error {
+	ids, err := s.taggedQuestions(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.MergeTags(ctx, alias, canonical); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.reindex(ctx, id)
+	}
+	return nil
+}
+
+// RenameTag implements TagStore
+func (s *IndexedStore) RenameTag(ctx context.Context, from, to string) error {
+	ids, err := s.taggedQuestions(ctx, from)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.RenameTag(ctx, from, to); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.reindex(ctx, id)
+	}
+	return nil
+}
+
+// taggedQuestions returns the IDs of the questions carrying a tag, whose
+// indexed tags go stale when the tag is merged or renamed
+func (s *IndexedStore) taggedQuestions(ctx context.Context, tag string) ([]int64, error) {
+	var ids []int64
+	params := ListQuestionsParams{Tags: []string{tag}, Sort: "created_at", Order: "asc", Limit: rebuildPageSize}
+	for {
+		page, err := s.Store.ListQuestions(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list questions tagged %q: %w", tag, err)
+		}
+		for _, q := range page {
+			ids = append(ids, q.ID)
+		}
+		if len(page) < params.Limit {
+			return ids, nil
+		}
+		last := CursorOf(page[len(page)-1])
+		params.After = &last
+	}
+}
+
+// withHits replaces the params' search query with the index's ranked matches,
+// at most limit of them when limit is positive
+func (s *IndexedStore) withHits(params ListQuestionsParams, limit int) ListQuestionsParams {
+	if params.Search == "" {
+		return params
+	}
+
+	hits := s.index.Search(params.Search, params.searchMode() == SearchModeBoolean, limit)
+	params.SearchHits = make(map[int64]float64, len(hits))
+	for _, hit := range hits {
+		params.SearchHits[hit.ID] = hit.Score
+	}
+	params.Search = ""
+	return params
+}
+
+// reindex reloads a question from the wrapped store into the index. Failures
+// only leave the index stale, so they are logged rather than returned after
+// the write itself succeeded.
+func (s *IndexedStore) reindex(ctx context.Context, id int64) {
+	q, err := s.Store.GetQuestion(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("reindexing question failed", "question_id", id, "error", err)
+		return
+	}
+	doc, err := s.document(ctx, q)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("reindexing question failed", "question_id", id, "error", err)
+		return
+	}
+	s.index.Put(doc)
+}
+
+// document collects the searchable text of a question: its title, content,
+// tags and the comments on it and its answers
+func (s *IndexedStore) document(ctx context.Context, q models.Question) (search.Document, error) {
+	doc := search.Document{ID: q.ID, Title: q.Title, Content: q.Content}
+
+	tags, err := s.Store.GetQuestionTags(ctx, q.ID)
+	if err != nil {
+		return doc, fmt.Errorf("failed to get tags of question %d: %w", q.ID, err)
+	}
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, tag.Name)
+	}
+
+	comments, err := s.Store.ListComments(ctx, q.ID)
+	if err != nil {
+		return doc, fmt.Errorf("failed to get comments of question %d: %w", q.ID, err)
+	}
+	for _, comment := range comments {
+		doc.Comments = append(doc.Comments, comment.Content)
+	}
+
+	answerComments, err := s.Store.ListAnswerComments(ctx, q.ID)
+	if err != nil {
+		return doc, fmt.Errorf("failed to get answer comments of question %d: %w", q.ID, err)
+	}
+	for _, byAnswer := range answerComments {
+		for _, comment := range byAnswer {
+			doc.Comments = append(doc.Comments, comment.Content)
+		}
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/search"
+)
+
+// newTestIndexedStore wraps an empty memory store with an empty index
+func newTestIndexedStore() *IndexedStore {
+	return NewIndexedStore(NewMemoryStore(), search.NewIndex(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestIndexedStoreBroadSearch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestIndexedStore()
+
+	// The oldest maxSearchHits questions rank best, so a capped search would
+	// only see them
+	total := maxSearchHits + 100
+	for i := 0; i < total; i++ {
+		content := "common"
+		if i < maxSearchHits {
+			content = strings.Repeat("common ", 5)
+		}
+		if _, err := s.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Question", Content: content}, nil); err != nil {
+			t.Fatalf("creating question %d: %v", i, err)
+		}
+	}
+
+	params := ListQuestionsParams{Search: "common", Sort: "created_at", Order: "desc", Limit: 3}
+	if n, err := s.CountQuestions(ctx, params); err != nil || n != total {
+		t.Errorf("CountQuestions = %d, %v; want %d", n, err, total)
+	}
+	questions, err := s.ListQuestions(ctx, params)
+	if err != nil {
+		t.Fatalf("ListQuestions: %v", err)
+	}
+	var ids []int64
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	if want := []int64{int64(total), int64(total - 1), int64(total - 2)}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("newest matches = %v, want %v", ids, want)
+	}
+
+	params.Sort = SortRelevance
+	if n, err := s.CountQuestions(ctx, params); err != nil || n != total {
+		t.Errorf("CountQuestions by relevance = %d, %v; want %d", n, err, total)
+	}
+}
+
+func TestIndexedStoreTagChanges(t *testing.T) {
+	ctx := context.Background()
+	s := newTestIndexedStore()
+
+	for _, tags := range [][]string{{"golang"}, {"go"}, {"rust"}} {
+		if _, err := s.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Question", Content: "Text", TagNames: tags}, nil); err != nil {
+			t.Fatalf("creating question tagged %v: %v", tags, err)
+		}
+	}
+	if err := s.MergeTags(ctx, "golang", "go"); err != nil {
+		t.Fatalf("MergeTags: %v", err)
+	}
+	if err := s.RenameTag(ctx, "rust", "rustlang"); err != nil {
+		t.Fatalf("RenameTag: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"golang", nil},
+		{"go", []int64{1, 2}},
+		{"rust", nil},
+		{"rustlang", []int64{3}},
+	}
+	for _, tt := range tests {
+		questions, err := s.ListQuestions(ctx, ListQuestionsParams{Search: tt.query, Sort: "created_at", Order: "asc", Limit: 10})
+		if err != nil {
+			t.Fatalf("searching %q: %v", tt.query, err)
+		}
+		var ids []int64
+		for _, q := range questions {
+			ids = append(ids, q.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("searching %q = %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/search"
+)
+
+// MemoryStore implements Store entirely in process memory. It is meant for
+// tests and local demos that should run without MySQL.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	questions    map[int64]*models.Question
+	tags         map[int64]*models.TagInfo // question counts are computed on read
+	tagsByName   map[string]int64
+	synonyms     map[string]models.TagSynonym // alias -> synonym
+	questionTags map[int64][]int64
+	comments     map[int64][]models.Comment // question ID -> comments on it and its answers
+	answers      map[int64]*models.Answer
+	revisions    map[int64][]models.QuestionRevision // question ID -> revisions, oldest first
+	likes        map[int64]map[string]time.Time      // question ID -> liker key -> liked at
+	viewStats    map[int64]map[string]DailyViews     // question ID -> day -> views
+	likeStats    map[int64]map[string]int            // question ID -> day -> likes given
+	users        map[int64]models.User
+
+	nextQuestionID int64
+	nextTagID      int64
+	nextCommentID  int64
+	nextAnswerID   int64
+	nextRevisionID int64
+	nextUserID     int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		questions:    make(map[int64]*models.Question),
+		tags:         make(map[int64]*models.TagInfo),
+		tagsByName:   make(map[string]int64),
+		synonyms:     make(map[string]models.TagSynonym),
+		questionTags: make(map[int64][]int64),
+		comments:     make(map[int64][]models.Comment),
+		answers:      make(map[int64]*models.Answer),
+		revisions:    make(map[int64][]models.QuestionRevision),
+		likes:        make(map[int64]map[string]time.Time),
+		viewStats:    make(map[int64]map[string]DailyViews),
+		likeStats:    make(map[int64]map[string]int),
+		users:        make(map[int64]models.User),
+	}
+}
+
+// matches reports whether a question passes the params' filters other than
+// the search query. The caller must hold mu.
+func (s *MemoryStore) matches(q *models.Question, params ListQuestionsParams) bool {
+	if len(params.Tags) > 0 {
+		matched := 0
+		for _, name := range params.Tags {
+			if s.hasTag(q.ID, name) {
+				matched++
+			}
+		}
+		if matched == 0 || (!params.MatchAnyTag && matched < len(params.Tags)) {
+			return false
+		}
+	}
+
+	for _, name := range params.ExcludeTags {
+		if s.hasTag(q.ID, name) {
+			return false
+		}
+	}
+
+	switch params.Status {
+	case QuestionStatusUnanswered:
+		return q.AnswerCount == 0
+	case QuestionStatusAnswered:
+		return q.AnswerCount > 0
+	case QuestionStatusAccepted:
+		return q.AcceptedAnswerID != nil
+	}
+
+	return true
+}
+
+// hasTag reports whether a question carries the named tag. The caller must hold mu.
+func (s *MemoryStore) hasTag(questionID int64, name string) bool {
+	tagID, ok := s.tagsByName[name]
+	return ok && containsID(s.questionTags[questionID], tagID)
+}
+
+// filtered returns the questions passing the params' filters and search query,
+// with their search relevance set. The caller must hold mu.
+func (s *MemoryStore) filtered(params ListQuestionsParams) []models.Question {
+	var terms []search.Term
+	if params.Search != "" && params.SearchHits == nil {
+		terms = search.ParseQuery(params.Search, params.searchMode() == SearchModeBoolean)
+	}
+
+	var result []models.Question
+	for _, q := range s.questions {
+		if !s.matches(q, params) {
+			continue
+		}
+		match := *q
+		switch {
+		case params.SearchHits != nil:
+			score, ok := params.SearchHits[q.ID]
+			if !ok {
+				continue
+			}
+			match.Relevance = score
+		case params.Search != "":
+			match.Relevance = searchScore(q, terms)
+			if match.Relevance == 0 {
+				continue
+			}
+		}
+		result = append(result, match)
+	}
+	return result
+}
+
+// ListQuestions implements QuestionStore
+func (s *MemoryStore) ListQuestions(ctx context.Context, params ListQuestionsParams) ([]models.Question, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := s.filtered(params)
+
+	sortField := params.sortField()
+	desc := params.Order != "asc"
+	sort.SliceStable(result, func(i, j int) bool {
+		cmp := compareQuestions(result[i], result[j], sortField)
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	// Keyset pagination continues right after the cursor's position
+	if params.After != nil {
+		after := models.Question{
+			ID:        params.After.ID,
+			CreatedAt: params.After.CreatedAt,
+			UpdatedAt: params.After.UpdatedAt,
+			LikeCount: params.After.LikeCount,
+			ViewCount: params.After.ViewCount,
+			Relevance: params.After.Relevance,
+		}
+		start := sort.Search(len(result), func(i int) bool {
+			cmp := compareQuestions(result[i], after, sortField)
+			if desc {
+				return cmp < 0
+			}
+			return cmp > 0
+		})
+		result = result[start:]
+	}
+
+	if params.Offset >= len(result) {
+		return []models.Question{}, nil
+	}
+	end := params.Offset + params.Limit
+	if params.Limit <= 0 || end > len(result) {
+		end = len(result)
+	}
+
+	return append([]models.Question{}, result[params.Offset:end]...), nil
+}
+
+// CountQuestions implements QuestionStore
+func (s *MemoryStore) CountQuestions(ctx context.Context, params ListQuestionsParams) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.filtered(params)), nil
+}
+
+// GetQuestion implements QuestionStore
+func (s *MemoryStore) GetQuestion(ctx context.Context, id int64) (models.Question, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.questions[id]
+	if !ok {
+		return models.Question{}, ErrNotFound
+	}
+	return *q, nil
+}
+
+// QuestionExists implements QuestionStore
+func (s *MemoryStore) QuestionExists(ctx context.Context, id int64) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.questions[id]
+	return ok, nil
+}
+
+// CreateQuestion implements QuestionStore
+func (s *MemoryStore) CreateQuestion(ctx context.Context, req models.QuestionCreateRequest, authorID *int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.nextQuestionID++
+	q := &models.Question{
+		ID:        s.nextQuestionID,
+		Title:     req.Title,
+		Content:   req.Content,
+		AuthorID:  authorID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.questions[q.ID] = q
+
+	s.attachTags(q.ID, req.TagNames)
+	s.recordRevision(q, authorID, nil)
+
+	return q.ID, nil
+}
+
+// attachTags links the named tags to a question, mapping synonyms to their
+// canonical tag and creating tags that do not exist yet. The caller must hold
+// mu for writing.
+func (s *MemoryStore) attachTags(questionID int64, tagNames []string) {
+	for _, tagName := range tagNames {
+		tagID, ok := s.tagsByName[tagName]
+		if synonym, isAlias := s.synonyms[tagName]; isAlias {
+			tagID, ok = synonym.TagID, true
+		}
+		if !ok {
+			s.nextTagID++
+			tagID = s.nextTagID
+			s.tags[tagID] = &models.TagInfo{
+				Tag:       models.Tag{ID: tagID, Name: tagName},
+				CreatedAt: time.Now(),
+			}
+			s.tagsByName[tagName] = tagID
+		}
+		if !containsID(s.questionTags[questionID], tagID) {
+			s.questionTags[questionID] = append(s.questionTags[questionID], tagID)
+		}
+	}
+}
+
+// UpdateQuestion implements QuestionStore
+func (s *MemoryStore) UpdateQuestion(ctx context.Context, id int64, update QuestionUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.questions[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if update.Title != nil {
+		q.Title = *update.Title
+	}
+	if update.Content != nil {
+		q.Content = *update.Content
+	}
+	if update.TagNames != nil {
+		delete(s.questionTags, id)
+		s.attachTags(id, *update.TagNames)
+	}
+	q.UpdatedAt = time.Now()
+	s.recordRevision(q, update.EditorID, update.RollbackOf)
+
+	return nil
+}
+
+// DeleteQuestion implements QuestionStore
+func (s *MemoryStore) DeleteQuestion(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.questions[id]; !ok {
+		return ErrNotFound
+	}
+
+	for answerID, a := range s.answers {
+		if a.QuestionID == id {
+			delete(s.answers, answerID)
+		}
+	}
+	delete(s.questionTags, id)
+	delete(s.revisions, id)
+	delete(s.comments, id)
+	delete(s.likes, id)
+	delete(s.viewStats, id)
+	delete(s.likeStats, id)
+	delete(s.questions, id)
+
+	return nil
+}
+
+// SetAcceptedAnswer implements QuestionStore
+func (s *MemoryStore) SetAcceptedAnswer(ctx context.Context, questionID int64, answerID *int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.questions[questionID]
+	if !ok {
+		return ErrNotFound
+	}
+	q.AcceptedAnswerID = answerID
+	return nil
+}
+
+// GetQuestionTags implements QuestionStore
+func (s *MemoryStore) GetQuestionTags(ctx context.Context, questionID int64) ([]models.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tags []models.Tag
+	for _, tagID := range s.questionTags[questionID] {
+		tags = append(tags, s.tags[tagID].Tag)
+	}
+	return tags, nil
+}
+
+// GetTagsForQuestions implements QuestionStore
+func (s *MemoryStore) GetTagsForQuestions(ctx context.Context, questionIDs []int64) (map[int64][]models.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tags := make(map[int64][]models.Tag, len(questionIDs))
+	for _, questionID := range questionIDs {
+		for _, tagID := range s.questionTags[questionID] {
+			tags[questionID] = append(tags[questionID], s.tags[tagID].Tag)
+		}
+	}
+	return tags, nil
+}
+
+// GetCounts implements QuestionStore
+func (s *MemoryStore) GetCounts(ctx context.Context, questionID int64) (int, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.questions[questionID]
+	if !ok {
+		return 0, 0, ErrNotFound
+	}
+	return q.ViewCount, q.LikeCount, nil
+}
+
+// GetCountsForQuestions implements QuestionStore
+func (s *MemoryStore) GetCountsForQuestions(ctx context.Context, questionIDs []int64) (map[int64]QuestionCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[int64]QuestionCounts, len(questionIDs))
+	for _, questionID := range questionIDs {
+		if q, ok := s.questions[questionID]; ok {
+			counts[questionID] = QuestionCounts{Views: q.ViewCount, Likes: q.LikeCount}
+		}
+	}
+	return counts, nil
+}
+
+// IncrementViewCount implements QuestionStore
+func (s *MemoryStore) IncrementViewCount(ctx context.Context, questionID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q, ok := s.questions[questionID]; ok {
+		q.ViewCount++
+	}
+	return nil
+}
+
+// AddViewCounts implements QuestionStore
+func (s *MemoryStore) AddViewCounts(ctx context.Context, deltas map[int64]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, delta := range deltas {
+		if q, ok := s.questions[id]; ok {
+			q.ViewCount += delta
+		}
+	}
+	return nil
+}
+
+// ListComments implements CommentStore
+func (s *MemoryStore) ListComments(ctx context.Context, questionID int64) ([]models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Comments are appended in creation order; return them newest first
+	stored := s.comments[questionID]
+	var comments []models.Comment
+	for i := len(stored) - 1; i >= 0; i-- {
+		if stored[i].AnswerID == nil {
+			comments = append(comments, stored[i])
+		}
+	}
+	return comments, nil
+}
+
+// ListAnswerComments implements CommentStore
+func (s *MemoryStore) ListAnswerComments(ctx context.Context, questionID int64) (map[int64][]models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comments := make(map[int64][]models.Comment)
+	for _, comment := range s.comments[questionID] {
+		if comment.AnswerID != nil {
+			comments[*comment.AnswerID] = append(comments[*comment.AnswerID], comment)
+		}
+	}
+	return comments, nil
+}
+
+// AddComment implements CommentStore
+func (s *MemoryStore) AddComment(ctx context.Context, comment models.Comment) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.questions[comment.QuestionID]; !ok {
+		return 0, ErrNotFound
+	}
+	if comment.AnswerID != nil {
+		if a, ok := s.answers[*comment.AnswerID]; !ok || a.QuestionID != comment.QuestionID {
+			return 0, ErrNotFound
+		}
+	}
+
+	s.nextCommentID++
+	comment.ID = s.nextCommentID
+	comment.CreatedAt = time.Now()
+	s.comments[comment.QuestionID] = append(s.comments[comment.QuestionID], comment)
+	return comment.ID, nil
+}
+
+// ToggleLike implements LikeStore
+func (s *MemoryStore) ToggleLike(ctx context.Context, questionID int64, userID *int64, clientIP string) (bool, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.questions[questionID]
+	if !ok {
+		return false, 0, ErrNotFound
+	}
+
+	likers := s.likes[questionID]
+	if likers == nil {
+		likers = make(map[string]time.Time)
+		s.likes[questionID] = likers
+	}
+
+	// Signed-in users are identified by account, anonymous visitors by IP
+	key := "ip:" + clientIP
+	if userID != nil {
+		key = fmt.Sprintf("user:%d", *userID)
+	}
+
+	if _, alreadyLiked := likers[key]; alreadyLiked {
+		delete(likers, key)
+		if q.LikeCount > 0 {
+			q.LikeCount--
+		}
+		return false, q.LikeCount, nil
+	}
+
+	now := time.Now()
+	likers[key] = now
+	q.LikeCount++
+
+	// Record the like on today's stats, where unliking does not remove it
+	if s.likeStats[questionID] == nil {
+		s.likeStats[questionID] = make(map[string]int)
+	}
+	s.likeStats[questionID][now.UTC().Format(models.StatsDateLayout)]++
+	return true, q.LikeCount, nil
+}
+
+// compareQuestions orders two questions by the sort field, then by ID
+func compareQuestions(a, b models.Question, sortField string) int {
+	var cmp int
+	switch sortField {
+	case "updated_at":
+		cmp = a.UpdatedAt.Compare(b.UpdatedAt)
+	case "like_count":
+		cmp = a.LikeCount - b.LikeCount
+	case "view_count":
+		cmp = a.ViewCount - b.ViewCount
+	case SortRelevance:
+		cmp = compareFloat(a.Relevance, b.Relevance)
+	default:
+		cmp = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if cmp == 0 && a.ID != b.ID {
+		if a.ID < b.ID {
+			return -1
+		}
+		return 1
+	}
+	return cmp
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
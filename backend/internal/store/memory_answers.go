@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// ListAnswers implements AnswerStore
+func (s *MemoryStore) ListAnswers(ctx context.Context, questionID int64, order string) ([]models.Answer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	answers := []models.Answer{}
+	for _, a := range s.answers {
+		if a.QuestionID == questionID {
+			answers = append(answers, *a)
+		}
+	}
+
+	// IDs increase with creation time, so they order answers by age
+	sort.Slice(answers, func(i, j int) bool {
+		a, b := answers[i], answers[j]
+		switch order {
+		case AnswerSortNewest:
+			return a.ID > b.ID
+		case AnswerSortOldest:
+			return a.ID < b.ID
+		default:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			return a.ID < b.ID
+		}
+	})
+
+	return answers, nil
+}
+
+// GetAnswer implements AnswerStore
+func (s *MemoryStore) GetAnswer(ctx context.Context, id int64) (models.Answer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.answers[id]
+	if !ok {
+		return models.Answer{}, ErrNotFound
+	}
+	return *a, nil
+}
+
+// CreateAnswer implements AnswerStore
+func (s *MemoryStore) CreateAnswer(ctx context.Context, answer models.Answer) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.questions[answer.QuestionID]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	now := time.Now()
+	s.nextAnswerID++
+	answer.ID = s.nextAnswerID
+	answer.Score = 0
+	answer.CreatedAt = now
+	answer.UpdatedAt = now
+	answer.Comments = nil
+	s.answers[answer.ID] = &answer
+	q.AnswerCount++
+
+	return answer.ID, nil
+}
+
+// UpdateAnswer implements AnswerStore
+func (s *MemoryStore) UpdateAnswer(ctx context.Context, id int64, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.answers[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.Content = content
+	a.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/questions/backend/internal/models"
+)
+
+func TestListAnswersOrder(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	questionID, err := s.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Sorted answers", Content: "In which order?"}, nil)
+	if err != nil {
+		t.Fatalf("creating question: %v", err)
+	}
+
+	// Answers 1 to 4 in creation order, scored 0, 3, 1 and 3
+	for _, score := range []int{0, 3, 1, 3} {
+		id, err := s.CreateAnswer(ctx, models.Answer{QuestionID: questionID, Content: "An answer"})
+		if err != nil {
+			t.Fatalf("creating answer: %v", err)
+		}
+		s.answers[id].Score = score
+	}
+
+	tests := []struct {
+		sort string
+		want []int64
+	}{
+		{AnswerSortScore, []int64{2, 4, 3, 1}},
+		{AnswerSortNewest, []int64{4, 3, 2, 1}},
+		{AnswerSortOldest, []int64{1, 2, 3, 4}},
+		{"", []int64{2, 4, 3, 1}},
+	}
+	for _, tt := range tests {
+		answers, err := s.ListAnswers(ctx, questionID, tt.sort)
+		if err != nil {
+			t.Fatalf("ListAnswers(%q): %v", tt.sort, err)
+		}
+		var ids []int64
+		for _, a := range answers {
+			ids = append(ids, a.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("ListAnswers(%q) = %v, want %v", tt.sort, ids, tt.want)
+		}
+	}
+}
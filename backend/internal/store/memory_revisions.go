@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// recordRevision snapshots a question's current state as its next revision.
+// The caller must hold mu for writing.
+func (s *MemoryStore) recordRevision(q *models.Question, editorID *int64, rollbackOf *int) {
+	tags := []string{}
+	for _, tagID := range s.questionTags[q.ID] {
+		tags = append(tags, s.tags[tagID].Name)
+	}
+	sort.Strings(tags)
+
+	s.nextRevisionID++
+	s.revisions[q.ID] = append(s.revisions[q.ID], models.QuestionRevision{
+		ID:         s.nextRevisionID,
+		QuestionID: q.ID,
+		Revision:   len(s.revisions[q.ID]) + 1,
+		Title:      q.Title,
+		Content:    q.Content,
+		Tags:       tags,
+		EditorID:   editorID,
+		RollbackOf: rollbackOf,
+		CreatedAt:  q.UpdatedAt,
+	})
+}
+
+// ListRevisions implements RevisionStore
+func (s *MemoryStore) ListRevisions(ctx context.Context, questionID int64) ([]models.QuestionRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.QuestionRevision{}, s.revisions[questionID]...), nil
+}
+
+// GetRevision implements RevisionStore
+func (s *MemoryStore) GetRevision(ctx context.Context, questionID int64, revision int) (models.QuestionRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := s.revisions[questionID]
+	if revision < 1 || revision > len(revisions) {
+		return models.QuestionRevision{}, ErrNotFound
+	}
+	return revisions[revision-1], nil
+}
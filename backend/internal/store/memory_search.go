@@ -0,0 +1,46 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/search"
+)
+
+// countTerm returns how often a term occurs in a document's words
+func countTerm(term search.Term, text string, words []string) int {
+	if term.Phrase {
+		return strings.Count(text, term.Text)
+	}
+	n := 0
+	for _, word := range words {
+		if word == term.Text || (term.Prefix && strings.HasPrefix(word, term.Text)) {
+			n++
+		}
+	}
+	return n
+}
+
+// searchScore ranks a question against the parsed terms by plain term
+// frequency, weighting title matches above body matches. A score of 0 means
+// the question does not match.
+func searchScore(q *models.Question, terms []search.Term) float64 {
+	titleWords := search.Words(q.Title)
+	contentWords := search.Words(q.Content)
+	title := strings.Join(titleWords, " ")
+	content := strings.Join(contentWords, " ")
+
+	var score float64
+	for _, term := range terms {
+		n := titleRelevanceWeight*countTerm(term, title, titleWords) + countTerm(term, content, contentWords)
+		switch {
+		case term.Excluded && n > 0:
+			return 0
+		case term.Required && n == 0:
+			return 0
+		case !term.Excluded:
+			score += float64(n)
+		}
+	}
+	return score
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// SaveViewStats implements StatsStore
+func (s *MemoryStore) SaveViewStats(ctx context.Context, day time.Time, views map[int64]DailyViews) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	date := day.Format(models.StatsDateLayout)
+	for id, v := range views {
+		if _, ok := s.questions[id]; !ok {
+			continue
+		}
+		if s.viewStats[id] == nil {
+			s.viewStats[id] = make(map[string]DailyViews)
+		}
+		s.viewStats[id][date] = v
+	}
+	return nil
+}
+
+// ListDailyStats implements StatsStore
+func (s *MemoryStore) ListDailyStats(ctx context.Context, questionID int64, from, to time.Time) ([]models.QuestionDayStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	first := from.Format(models.StatsDateLayout)
+	last := to.Format(models.StatsDateLayout)
+	byDay := make(map[string]*models.QuestionDayStats)
+	day := func(date string) *models.QuestionDayStats {
+		if byDay[date] == nil {
+			byDay[date] = &models.QuestionDayStats{Date: date}
+		}
+		return byDay[date]
+	}
+
+	for date, v := range s.viewStats[questionID] {
+		if date >= first && date <= last {
+			stat := day(date)
+			stat.Hits = v.Hits
+			stat.UniqueVisitors = v.UniqueVisitors
+		}
+	}
+	for date, likes := range s.likeStats[questionID] {
+		if date >= first && date <= last {
+			day(date).Likes = likes
+		}
+	}
+
+	stats := make([]models.QuestionDayStats, 0, len(byDay))
+	for _, stat := range byDay {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Date < stats[j].Date })
+	return stats, nil
+}
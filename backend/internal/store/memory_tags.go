@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// tagInfos returns every tag matching the params' prefix with its question
+// count. The caller must hold mu.
+func (s *MemoryStore) tagInfos(params ListTagsParams) []models.TagInfo {
+	counts := make(map[int64]int)
+	for _, tagIDs := range s.questionTags {
+		for _, tagID := range tagIDs {
+			counts[tagID]++
+		}
+	}
+
+	var result []models.TagInfo
+	for id, tag := range s.tags {
+		if !strings.HasPrefix(tag.Name, params.Prefix) {
+			continue
+		}
+		info := *tag
+		info.QuestionCount = counts[id]
+		result = append(result, info)
+	}
+	return result
+}
+
+// ListTags implements TagStore
+func (s *MemoryStore) ListTags(ctx context.Context, params ListTagsParams) ([]models.TagInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := s.tagInfos(params)
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch params.Sort {
+		case TagSortName:
+			return a.Name < b.Name
+		case TagSortNewest:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			return a.ID > b.ID
+		default:
+			if a.QuestionCount != b.QuestionCount {
+				return a.QuestionCount > b.QuestionCount
+			}
+			return a.Name < b.Name
+		}
+	})
+
+	if params.Offset >= len(result) {
+		return []models.TagInfo{}, nil
+	}
+	end := params.Offset + params.Limit
+	if params.Limit <= 0 || end > len(result) {
+		end = len(result)
+	}
+	return append([]models.TagInfo{}, result[params.Offset:end]...), nil
+}
+
+// CountTags implements TagStore
+func (s *MemoryStore) CountTags(ctx context.Context, params ListTagsParams) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.tagInfos(ListTagsParams{Prefix: params.Prefix})), nil
+}
+
+// GetTag implements TagStore
+func (s *MemoryStore) GetTag(ctx context.Context, name string) (models.TagInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tagID, ok := s.tagsByName[name]
+	if !ok {
+		return models.TagInfo{}, ErrNotFound
+	}
+
+	info := *s.tags[tagID]
+	for _, tagIDs := range s.questionTags {
+		if containsID(tagIDs, tagID) {
+			info.QuestionCount++
+		}
+	}
+	return info, nil
+}
+
+// UpdateTagDescription implements TagStore
+func (s *MemoryStore) UpdateTagDescription(ctx context.Context, name, description string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tagID, ok := s.tagsByName[name]
+	if !ok {
+		return ErrNotFound
+	}
+	s.tags[tagID].Description = description
+	return nil
+}
+
+// ResolveTags implements TagStore
+func (s *MemoryStore) ResolveTags(ctx context.Context, names []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return resolveNames(names, func(name string) (string, bool) {
+		synonym, ok := s.synonyms[name]
+		if !ok {
+			return "", false
+		}
+		return s.tags[synonym.TagID].Name, true
+	}), nil
+}
+
+// MergeTags implements TagStore
+func (s *MemoryStore) MergeTags(ctx context.Context, alias, canonical string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	canonicalID, ok := s.tagsByName[canonical]
+	if !ok {
+		return ErrNotFound
+	}
+	if alias == canonical {
+		return nil
+	}
+
+	if aliasID, ok := s.tagsByName[alias]; ok && aliasID != canonicalID {
+		for questionID, tagIDs := range s.questionTags {
+			if !containsID(tagIDs, aliasID) {
+				continue
+			}
+			var retagged []int64
+			for _, tagID := range tagIDs {
+				if tagID != aliasID {
+					retagged = append(retagged, tagID)
+				}
+			}
+			if !containsID(retagged, canonicalID) {
+				retagged = append(retagged, canonicalID)
+			}
+			s.questionTags[questionID] = retagged
+		}
+
+		for name, synonym := range s.synonyms {
+			if synonym.TagID == aliasID {
+				synonym.TagID = canonicalID
+				s.synonyms[name] = synonym
+			}
+		}
+
+		if s.tags[canonicalID].Description == "" {
+			s.tags[canonicalID].Description = s.tags[aliasID].Description
+		}
+		delete(s.tags, aliasID)
+		delete(s.tagsByName, alias)
+	}
+
+	s.synonyms[alias] = models.TagSynonym{Alias: alias, TagID: canonicalID, CreatedAt: time.Now()}
+	return nil
+}
+
+// RenameTag implements TagStore
+func (s *MemoryStore) RenameTag(ctx context.Context, from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tagID, ok := s.tagsByName[from]
+	if !ok {
+		return ErrNotFound
+	}
+	if from == to {
+		return nil
+	}
+	if _, taken := s.tagsByName[to]; taken {
+		return ErrConflict
+	}
+
+	delete(s.tagsByName, from)
+	s.tagsByName[to] = tagID
+	s.tags[tagID].Name = to
+	return nil
+}
+
+// ListTagSynonyms implements TagStore
+func (s *MemoryStore) ListTagSynonyms(ctx context.Context, tagName string) ([]models.TagSynonym, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	synonyms := []models.TagSynonym{}
+	for _, synonym := range s.synonyms {
+		synonym.TagName = s.tags[synonym.TagID].Name
+		if tagName == "" || synonym.TagName == tagName {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	sort.Slice(synonyms, func(i, j int) bool {
+		return synonyms[i].Alias < synonyms[j].Alias
+	})
+	return synonyms, nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// CreateUser implements UserStore
+func (s *MemoryStore) CreateUser(ctx context.Context, user models.User) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, user.Username) || strings.EqualFold(u.Email, user.Email) {
+			return 0, ErrConflict
+		}
+	}
+
+	s.nextUserID++
+	user.ID = s.nextUserID
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	user.CreatedAt = time.Now()
+	s.users[user.ID] = user
+	return user.ID, nil
+}
+
+// GetUserByID implements UserStore
+func (s *MemoryStore) GetUserByID(ctx context.Context, id int64) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+// GetUserByLogin implements UserStore
+func (s *MemoryStore) GetUserByLogin(ctx context.Context, login string) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, login) || strings.EqualFold(u.Email, login) {
+			return u, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+// SetUserRole implements UserStore
+func (s *MemoryStore) SetUserRole(ctx context.Context, id int64, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.Role = role
+	s.users[id] = user
+	return nil
+}
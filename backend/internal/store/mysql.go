@@ -0,0 +1,696 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// MySQLStore implements Store on top of a MySQL database
+type MySQLStore struct {
+	db *sql.DB
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// NewMySQLStore creates a store backed by the given database handle
+func NewMySQLStore(db *sql.DB) *MySQLStore {
+	return &MySQLStore{db: db}
+}
+
+var mysqlSortColumns = map[string]string{
+	"created_at": "q.created_at",
+	"updated_at": "q.updated_at",
+	"like_count": "q.like_count",
+	"view_count": "q.view_count",
+}
+
+// titleRelevanceWeight is how much more a title match counts than a body match
+const titleRelevanceWeight = 3
+
+// matchAgainst returns the AGAINST clause for the params' search mode
+func matchAgainst(params ListQuestionsParams) string {
+	if params.searchMode() == SearchModeBoolean {
+		return "AGAINST(? IN BOOLEAN MODE)"
+	}
+	return "AGAINST(? IN NATURAL LANGUAGE MODE)"
+}
+
+// questionFilter builds the WHERE clause shared by the list and count
+// queries. Tag filters use subqueries rather than joins so that a question
+// carrying several of the tags is neither listed nor counted twice.
+func questionFilter(params ListQuestionsParams) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if len(params.Tags) > 0 {
+		placeholders, tagArgs := inPlaceholders(params.Tags)
+		if params.MatchAnyTag {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM question_tags qt JOIN tags t ON qt.tag_id = t.id"+
+				" WHERE qt.question_id = q.id AND t.name IN ("+placeholders+"))")
+			args = append(args, tagArgs...)
+		} else {
+			conditions = append(conditions, "(SELECT COUNT(DISTINCT t.id) FROM question_tags qt JOIN tags t ON qt.tag_id = t.id"+
+				" WHERE qt.question_id = q.id AND t.name IN ("+placeholders+")) = ?")
+			args = append(args, tagArgs...)
+			args = append(args, len(params.Tags))
+		}
+	}
+
+	if len(params.ExcludeTags) > 0 {
+		placeholders, tagArgs := inPlaceholders(params.ExcludeTags)
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM question_tags qt JOIN tags t ON qt.tag_id = t.id"+
+			" WHERE qt.question_id = q.id AND t.name IN ("+placeholders+"))")
+		args = append(args, tagArgs...)
+	}
+
+	switch {
+	case params.SearchHits != nil && len(params.SearchHits) == 0:
+		conditions = append(conditions, "FALSE")
+	case params.SearchHits != nil:
+		placeholders := make([]string, 0, len(params.SearchHits))
+		for id := range params.SearchHits {
+			placeholders = append(placeholders, "?")
+			args = append(args, id)
+		}
+		conditions = append(conditions, "q.id IN ("+strings.Join(placeholders, ", ")+")")
+	case params.Search != "":
+		conditions = append(conditions, "MATCH(q.title, q.content) "+matchAgainst(params))
+		args = append(args, params.Search)
+	}
+
+	switch params.Status {
+	case QuestionStatusUnanswered:
+		conditions = append(conditions, "q.answer_count = 0")
+	case QuestionStatusAnswered:
+		conditions = append(conditions, "q.answer_count > 0")
+	case QuestionStatusAccepted:
+		conditions = append(conditions, "q.accepted_answer_id IS NOT NULL")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// idPlaceholders returns the placeholder list and arguments for an IN clause over IDs
+func idPlaceholders(ids []int64) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// inPlaceholders returns the placeholder list and arguments for an IN clause over values
+func inPlaceholders(values []string) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = "?"
+		args[i] = v
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// ListQuestions implements QuestionStore
+func (s *MySQLStore) ListQuestions(ctx context.Context, params ListQuestionsParams) ([]models.Question, error) {
+	filter, filterArgs := questionFilter(params)
+
+	// Relevance weights title matches above body matches; it is 0 without a search
+	relevance := "0"
+	var args []interface{}
+	switch {
+	case len(params.SearchHits) > 0:
+		var cases strings.Builder
+		cases.WriteString("CASE q.id")
+		for id, score := range params.SearchHits {
+			cases.WriteString(" WHEN ? THEN ?")
+			args = append(args, id, score)
+		}
+		cases.WriteString(" ELSE 0 END")
+		relevance = cases.String()
+	case params.SearchHits == nil && params.Search != "":
+		against := matchAgainst(params)
+		relevance = fmt.Sprintf("(%d * MATCH(q.title) %s + MATCH(q.content) %s)", titleRelevanceWeight, against, against)
+		args = append(args, params.Search, params.Search)
+	}
+	args = append(args, filterArgs...)
+
+	sortField := params.sortField()
+	column := "relevance"
+	if sortField != SortRelevance {
+		column = mysqlSortColumns[sortField]
+	}
+	order := "DESC"
+	if params.Order == "asc" {
+		order = "ASC"
+	}
+
+	// Keyset pagination continues after the cursor's (sort value, id). The
+	// computed relevance can only be compared in HAVING.
+	having := ""
+	if params.After != nil {
+		op := "<"
+		if order == "ASC" {
+			op = ">"
+		}
+		value := keysetValue(sortField, *params.After)
+		condition := fmt.Sprintf("(%s %s ? OR (%s = ? AND q.id %s ?))", column, op, column, op)
+		switch {
+		case sortField == SortRelevance:
+			having = " HAVING " + condition
+		case filter == "":
+			filter = " WHERE " + condition
+		default:
+			filter += " AND " + condition
+		}
+		args = append(args, value, value, params.After.ID)
+	}
+
+	query := "SELECT q.id, q.title, q.content, q.author_id, q.created_at, q.updated_at, q.like_count, q.view_count, q.answer_count, q.accepted_answer_id, " +
+		relevance + " AS relevance FROM questions q" +
+		filter + having + fmt.Sprintf(" ORDER BY %s %s, q.id %s LIMIT ? OFFSET ?", column, order, order)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []models.Question{}
+	for rows.Next() {
+		var q models.Question
+		if err := rows.Scan(&q.ID, &q.Title, &q.Content, &q.AuthorID, &q.CreatedAt, &q.UpdatedAt, &q.LikeCount, &q.ViewCount, &q.AnswerCount, &q.AcceptedAnswerID, &q.Relevance); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+
+	return questions, rows.Err()
+}
+
+// keysetValue returns the cursor's value of the sort field
+func keysetValue(sortField string, cursor QuestionCursor) interface{} {
+	switch sortField {
+	case "updated_at":
+		return cursor.UpdatedAt
+	case "like_count":
+		return cursor.LikeCount
+	case "view_count":
+		return cursor.ViewCount
+	case SortRelevance:
+		return cursor.Relevance
+	}
+	return cursor.CreatedAt
+}
+
+// CountQuestions implements QuestionStore
+func (s *MySQLStore) CountQuestions(ctx context.Context, params ListQuestionsParams) (int, error) {
+	filter, args := questionFilter(params)
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM questions q"+filter, args...).Scan(&total)
+	return total, err
+}
+
+// GetQuestion implements QuestionStore
+func (s *MySQLStore) GetQuestion(ctx context.Context, id int64) (models.Question, error) {
+	query := `SELECT id, title, content, author_id, created_at, updated_at, like_count, view_count, answer_count, accepted_answer_id
+			  FROM questions WHERE id = ?`
+
+	var question models.Question
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&question.ID, &question.Title, &question.Content, &question.AuthorID,
+		&question.CreatedAt, &question.UpdatedAt,
+		&question.LikeCount, &question.ViewCount, &question.AnswerCount, &question.AcceptedAnswerID,
+	)
+	if err == sql.ErrNoRows {
+		return question, ErrNotFound
+	}
+	return question, err
+}
+
+// QuestionExists implements QuestionStore
+func (s *MySQLStore) QuestionExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM questions WHERE id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// CreateQuestion implements QuestionStore
+func (s *MySQLStore) CreateQuestion(ctx context.Context, req models.QuestionCreateRequest, authorID *int64) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO questions (title, content, author_id) VALUES (?, ?, ?)",
+		req.Title, req.Content, authorID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	questionID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get question ID: %w", err)
+	}
+
+	if err := attachTags(ctx, tx, questionID, req.TagNames); err != nil {
+		return 0, err
+	}
+
+	if err := recordRevision(ctx, tx, questionID, authorID, nil); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return questionID, nil
+}
+
+// attachTags links the named tags to a question, mapping synonyms to their
+// canonical tag and creating tags that do not exist yet
+func attachTags(ctx context.Context, tx *sql.Tx, questionID int64, tagNames []string) error {
+	for _, tagName := range tagNames {
+		// Try to find a synonym or existing tag, or create a new one
+		var tagID int64
+		err := tx.QueryRowContext(ctx,
+			"SELECT tag_id FROM tag_synonyms WHERE alias = ? UNION ALL SELECT id FROM tags WHERE name = ? LIMIT 1",
+			tagName, tagName,
+		).Scan(&tagID)
+		if err == sql.ErrNoRows {
+			res, err := tx.ExecContext(ctx, "INSERT INTO tags (name) VALUES (?)", tagName)
+			if err != nil {
+				return fmt.Errorf("failed to create tag: %w", err)
+			}
+			tagID, err = res.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get tag ID: %w", err)
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to check tag existence: %w", err)
+		}
+
+		// IGNORE skips names repeated in the same request, including an alias
+		// next to its canonical name
+		_, err = tx.ExecContext(ctx,
+			"INSERT IGNORE INTO question_tags (question_id, tag_id) VALUES (?, ?)",
+			questionID, tagID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to associate tag with question: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateQuestion implements QuestionStore
+func (s *MySQLStore) UpdateQuestion(ctx context.Context, id int64, update QuestionUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the row so concurrent edits apply one after the other
+	var locked int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM questions WHERE id = ? FOR UPDATE", id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to lock question: %w", err)
+	}
+
+	sets := []string{"updated_at = CURRENT_TIMESTAMP"}
+	var args []interface{}
+	if update.Title != nil {
+		sets = append(sets, "title = ?")
+		args = append(args, *update.Title)
+	}
+	if update.Content != nil {
+		sets = append(sets, "content = ?")
+		args = append(args, *update.Content)
+	}
+	args = append(args, id)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE questions SET "+strings.Join(sets, ", ")+" WHERE id = ?", args...); err != nil {
+		return fmt.Errorf("failed to update question: %w", err)
+	}
+
+	if update.TagNames != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM question_tags WHERE question_id = ?", id); err != nil {
+			return fmt.Errorf("failed to remove question tags: %w", err)
+		}
+		if err := attachTags(ctx, tx, id, *update.TagNames); err != nil {
+			return err
+		}
+	}
+
+	if err := recordRevision(ctx, tx, id, update.EditorID, update.RollbackOf); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteQuestion implements QuestionStore
+func (s *MySQLStore) DeleteQuestion(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Remove dependent rows explicitly rather than relying on ON DELETE CASCADE,
+	// which databases bootstrapped outside the migrations may lack
+	dependents := []string{
+		"DELETE FROM question_tags WHERE question_id = ?",
+		"DELETE FROM question_revisions WHERE question_id = ?",
+		"DELETE FROM comments WHERE question_id = ?",
+		"DELETE FROM likes WHERE question_id = ?",
+		"DELETE FROM question_view_stats WHERE question_id = ?",
+		"UPDATE questions SET accepted_answer_id = NULL WHERE id = ?",
+		"DELETE FROM answers WHERE question_id = ?",
+	}
+	for _, stmt := range dependents {
+		if _, err := tx.ExecContext(ctx, stmt, id); err != nil {
+			return fmt.Errorf("failed to delete question data: %w", err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM questions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetAcceptedAnswer implements QuestionStore
+func (s *MySQLStore) SetAcceptedAnswer(ctx context.Context, questionID int64, answerID *int64) error {
+	// Accepting an answer is not an edit, so keep updated_at unchanged
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE questions SET accepted_answer_id = ?, updated_at = updated_at WHERE id = ?", answerID, questionID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		// RowsAffected counts changed rows only, so an unchanged row is not an error
+		if exists, err := s.QuestionExists(ctx, questionID); err != nil {
+			return err
+		} else if !exists {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// GetTagsForQuestions implements QuestionStore
+func (s *MySQLStore) GetTagsForQuestions(ctx context.Context, questionIDs []int64) (map[int64][]models.Tag, error) {
+	tags := make(map[int64][]models.Tag, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return tags, nil
+	}
+
+	placeholders, args := idPlaceholders(questionIDs)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT qt.question_id, t.id, t.name
+		FROM question_tags qt
+		JOIN tags t ON t.id = qt.tag_id
+		WHERE qt.question_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionID int64
+		var tag models.Tag
+		if err := rows.Scan(&questionID, &tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags[questionID] = append(tags[questionID], tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetQuestionTags implements QuestionStore
+func (s *MySQLStore) GetQuestionTags(ctx context.Context, questionID int64) ([]models.Tag, error) {
+	query := `
+		SELECT t.id, t.name
+		FROM tags t
+		JOIN question_tags qt ON t.id = qt.tag_id
+		WHERE qt.question_id = ?
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetCounts implements QuestionStore
+func (s *MySQLStore) GetCounts(ctx context.Context, questionID int64) (int, int, error) {
+	var viewCount, likeCount int
+	err := s.db.QueryRowContext(ctx, "SELECT view_count, like_count FROM questions WHERE id = ?", questionID).
+		Scan(&viewCount, &likeCount)
+	if err == sql.ErrNoRows {
+		return 0, 0, ErrNotFound
+	}
+	return viewCount, likeCount, err
+}
+
+// GetCountsForQuestions implements QuestionStore
+func (s *MySQLStore) GetCountsForQuestions(ctx context.Context, questionIDs []int64) (map[int64]QuestionCounts, error) {
+	counts := make(map[int64]QuestionCounts, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := idPlaceholders(questionIDs)
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, view_count, like_count FROM questions WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var c QuestionCounts
+		if err := rows.Scan(&id, &c.Views, &c.Likes); err != nil {
+			return nil, err
+		}
+		counts[id] = c
+	}
+	return counts, rows.Err()
+}
+
+// IncrementViewCount implements QuestionStore. A view is not an edit, so
+// updated_at keeps its value.
+func (s *MySQLStore) IncrementViewCount(ctx context.Context, questionID int64) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE questions SET view_count = view_count + 1, updated_at = updated_at WHERE id = ?", questionID)
+	return err
+}
+
+// AddViewCounts implements QuestionStore
+func (s *MySQLStore) AddViewCounts(ctx context.Context, deltas map[int64]int) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	var cases strings.Builder
+	var args []interface{}
+	ids := make([]int64, 0, len(deltas))
+	for id, delta := range deltas {
+		cases.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, delta)
+		ids = append(ids, id)
+	}
+	placeholders, idArgs := idPlaceholders(ids)
+
+	// A view is not an edit, so updated_at keeps its value
+	query := "UPDATE questions SET view_count = view_count + CASE id" + cases.String() +
+		" ELSE 0 END, updated_at = updated_at WHERE id IN (" + placeholders + ")"
+	if _, err := s.db.ExecContext(ctx, query, append(args, idArgs...)...); err != nil {
+		return fmt.Errorf("failed to add view counts: %w", err)
+	}
+	return nil
+}
+
+// ListComments implements CommentStore
+func (s *MySQLStore) ListComments(ctx context.Context, questionID int64) ([]models.Comment, error) {
+	query := `
+		SELECT id, question_id, answer_id, content, author_id, created_at
+		FROM comments
+		WHERE question_id = ? AND answer_id IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// ListAnswerComments implements CommentStore
+func (s *MySQLStore) ListAnswerComments(ctx context.Context, questionID int64) (map[int64][]models.Comment, error) {
+	query := `
+		SELECT id, question_id, answer_id, content, author_id, created_at
+		FROM comments
+		WHERE question_id = ? AND answer_id IS NOT NULL
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make(map[int64][]models.Comment)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments[*comment.AnswerID] = append(comments[*comment.AnswerID], comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func scanComment(rows *sql.Rows) (models.Comment, error) {
+	var comment models.Comment
+	err := rows.Scan(&comment.ID, &comment.QuestionID, &comment.AnswerID, &comment.Content, &comment.AuthorID, &comment.CreatedAt)
+	return comment, err
+}
+
+// AddComment implements CommentStore
+func (s *MySQLStore) AddComment(ctx context.Context, comment models.Comment) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		"INSERT INTO comments (question_id, answer_id, content, author_id) VALUES (?, ?, ?, ?)",
+		comment.QuestionID, comment.AnswerID, comment.Content, comment.AuthorID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ToggleLike implements LikeStore
+func (s *MySQLStore) ToggleLike(ctx context.Context, questionID int64, userID *int64, clientIP string) (bool, int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Signed-in users are identified by account, anonymous visitors by IP
+	likerClause := "client_ip = ? AND author_id IS NULL"
+	var liker interface{} = clientIP
+	if userID != nil {
+		likerClause = "author_id = ?"
+		liker = *userID
+	}
+
+	// Check if this liker has already liked this question
+	var alreadyLiked bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM likes WHERE question_id = ? AND "+likerClause+")",
+		questionID, liker).Scan(&alreadyLiked)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check like status: %w", err)
+	}
+
+	// A like is not an edit, so updated_at keeps its value
+	if !alreadyLiked {
+		var err error
+		if userID != nil {
+			_, err = tx.ExecContext(ctx, "INSERT INTO likes (question_id, author_id) VALUES (?, ?)", questionID, *userID)
+		} else {
+			_, err = tx.ExecContext(ctx, "INSERT INTO likes (question_id, client_ip) VALUES (?, ?)", questionID, clientIP)
+		}
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to add like: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE questions SET like_count = like_count + 1, updated_at = updated_at WHERE id = ?", questionID); err != nil {
+			return false, 0, fmt.Errorf("failed to update like count: %w", err)
+		}
+		// Record the like on today's stats, where unliking does not remove it
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO question_view_stats (question_id, day, likes) VALUES (?, ?, 1)
+			ON DUPLICATE KEY UPDATE likes = likes + 1`,
+			questionID, time.Now().UTC().Format(models.StatsDateLayout)); err != nil {
+			return false, 0, fmt.Errorf("failed to record daily like: %w", err)
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM likes WHERE question_id = ? AND "+likerClause, questionID, liker); err != nil {
+			return false, 0, fmt.Errorf("failed to remove like: %w", err)
+		}
+		// Ensure the like count doesn't go below 0
+		if _, err := tx.ExecContext(ctx, "UPDATE questions SET like_count = GREATEST(like_count - 1, 0), updated_at = updated_at WHERE id = ?", questionID); err != nil {
+			return false, 0, fmt.Errorf("failed to update like count: %w", err)
+		}
+	}
+
+	var likeCount int
+	if err := tx.QueryRowContext(ctx, "SELECT like_count FROM questions WHERE id = ?", questionID).Scan(&likeCount); err != nil {
+		return false, 0, fmt.Errorf("failed to get updated like count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return !alreadyLiked, likeCount, nil
+}
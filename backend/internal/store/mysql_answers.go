@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/questions/backend/internal/models"
+)
+
+var mysqlAnswerOrders = map[string]string{
+	AnswerSortScore:  "score DESC, created_at ASC, id ASC",
+	AnswerSortNewest: "created_at DESC, id DESC",
+	AnswerSortOldest: "created_at ASC, id ASC",
+}
+
+// ListAnswers implements AnswerStore
+func (s *MySQLStore) ListAnswers(ctx context.Context, questionID int64, sort string) ([]models.Answer, error) {
+	order, ok := mysqlAnswerOrders[sort]
+	if !ok {
+		order = mysqlAnswerOrders[AnswerSortScore]
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, question_id, author_id, content, score, created_at, updated_at
+		 FROM answers WHERE question_id = ? ORDER BY `+order, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []models.Answer{}
+	for rows.Next() {
+		var a models.Answer
+		if err := rows.Scan(&a.ID, &a.QuestionID, &a.AuthorID, &a.Content, &a.Score, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+// GetAnswer implements AnswerStore
+func (s *MySQLStore) GetAnswer(ctx context.Context, id int64) (models.Answer, error) {
+	var a models.Answer
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, question_id, author_id, content, score, created_at, updated_at
+		 FROM answers WHERE id = ?`, id,
+	).Scan(&a.ID, &a.QuestionID, &a.AuthorID, &a.Content, &a.Score, &a.CreatedAt, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return a, ErrNotFound
+	}
+	return a, err
+}
+
+// CreateAnswer implements AnswerStore
+func (s *MySQLStore) CreateAnswer(ctx context.Context, answer models.Answer) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO answers (question_id, author_id, content) VALUES (?, ?, ?)",
+		answer.QuestionID, answer.AuthorID, answer.Content,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	answerID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get answer ID: %w", err)
+	}
+
+	// A new answer is not an edit of the question, so updated_at keeps its value
+	if _, err := tx.ExecContext(ctx, "UPDATE questions SET answer_count = answer_count + 1, updated_at = updated_at WHERE id = ?", answer.QuestionID); err != nil {
+		return 0, fmt.Errorf("failed to update answer count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return answerID, nil
+}
+
+// UpdateAnswer implements AnswerStore
+func (s *MySQLStore) UpdateAnswer(ctx context.Context, id int64, content string) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE answers SET content = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", content, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		// RowsAffected counts changed rows only, so check the answer really is missing
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM answers WHERE id = ?)", id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
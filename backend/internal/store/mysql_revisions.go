@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// recordRevision snapshots the question's current title, content and tags as
+// its next revision, noting the revision it restored for rollbacks. It must
+// run in the transaction that changed the question.
+func recordRevision(ctx context.Context, tx *sql.Tx, questionID int64, editorID *int64, rollbackOf *int) error {
+	var title, content string
+	err := tx.QueryRowContext(ctx, "SELECT title, content FROM questions WHERE id = ?", questionID).
+		Scan(&title, &content)
+	if err != nil {
+		return fmt.Errorf("failed to read question for revision: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.name
+		FROM tags t
+		JOIN question_tags qt ON t.id = qt.tag_id
+		WHERE qt.question_id = ?
+		ORDER BY t.name
+	`, questionID)
+	if err != nil {
+		return fmt.Errorf("failed to read tags for revision: %w", err)
+	}
+	tags := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tags = append(tags, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO question_revisions (question_id, revision, title, content, tags, editor_id, rollback_of)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ?
+		FROM question_revisions
+		WHERE question_id = ?
+	`, questionID, title, content, string(tagsJSON), editorID, rollbackOf, questionID)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions implements RevisionStore
+func (s *MySQLStore) ListRevisions(ctx context.Context, questionID int64) ([]models.QuestionRevision, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, question_id, revision, title, content, tags, editor_id, rollback_of, created_at
+		FROM question_revisions
+		WHERE question_id = ?
+		ORDER BY revision
+	`, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.QuestionRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision implements RevisionStore
+func (s *MySQLStore) GetRevision(ctx context.Context, questionID int64, revision int) (models.QuestionRevision, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, question_id, revision, title, content, tags, editor_id, rollback_of, created_at
+		FROM question_revisions
+		WHERE question_id = ? AND revision = ?
+	`, questionID, revision)
+
+	rev, err := scanRevision(row)
+	if err == sql.ErrNoRows {
+		return models.QuestionRevision{}, ErrNotFound
+	}
+	return rev, err
+}
+
+// scanRevision reads one question_revisions row from a *sql.Row or *sql.Rows
+func scanRevision(row rowScanner) (models.QuestionRevision, error) {
+	var rev models.QuestionRevision
+	var tags []byte
+	if err := row.Scan(&rev.ID, &rev.QuestionID, &rev.Revision, &rev.Title, &rev.Content,
+		&tags, &rev.EditorID, &rev.RollbackOf, &rev.CreatedAt); err != nil {
+		return rev, err
+	}
+	if err := json.Unmarshal(tags, &rev.Tags); err != nil {
+		return rev, fmt.Errorf("failed to decode revision tags: %w", err)
+	}
+	return rev, nil
+}
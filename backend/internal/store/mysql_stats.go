@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// SaveViewStats implements StatsStore. It leaves the likes recorded for the day alone.
+func (s *MySQLStore) SaveViewStats(ctx context.Context, day time.Time, views map[int64]DailyViews) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	// Joining on questions skips questions deleted since they were viewed
+	rows := make([]string, 0, len(views))
+	args := []interface{}{day.Format(models.StatsDateLayout)}
+	for id, v := range views {
+		rows = append(rows, "SELECT ? AS id, ? AS views, ? AS unique_visitors")
+		args = append(args, id, v.Hits, v.UniqueVisitors)
+	}
+	query := `
+		INSERT INTO question_view_stats (question_id, day, views, unique_visitors)
+		SELECT q.id, ?, v.views, v.unique_visitors
+		FROM (` + strings.Join(rows, " UNION ALL ") + `) v
+		JOIN questions q ON q.id = v.id
+		ON DUPLICATE KEY UPDATE views = v.views, unique_visitors = v.unique_visitors`
+
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to save view stats: %w", err)
+	}
+	return nil
+}
+
+// ListDailyStats implements StatsStore
+func (s *MySQLStore) ListDailyStats(ctx context.Context, questionID int64, from, to time.Time) ([]models.QuestionDayStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, views, unique_visitors, likes
+		FROM question_view_stats
+		WHERE question_id = ? AND day BETWEEN ? AND ?
+		ORDER BY day`,
+		questionID, from.Format(models.StatsDateLayout), to.Format(models.StatsDateLayout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.QuestionDayStats{}
+	for rows.Next() {
+		var day time.Time
+		var stat models.QuestionDayStats
+		if err := rows.Scan(&day, &stat.Hits, &stat.UniqueVisitors, &stat.Likes); err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats: %w", err)
+		}
+		stat.Date = day.Format(models.StatsDateLayout)
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/questions/backend/internal/models"
+)
+
+var mysqlTagOrders = map[string]string{
+	TagSortPopular: "question_count DESC, t.name ASC",
+	TagSortName:    "t.name ASC",
+	TagSortNewest:  "t.created_at DESC, t.id DESC",
+}
+
+const tagInfoColumns = "t.id, t.name, COALESCE(t.description, ''), t.created_at, " +
+	"(SELECT COUNT(*) FROM question_tags qt WHERE qt.tag_id = t.id) AS question_count"
+
+// tagFilter builds the WHERE clause shared by the tag list and count queries
+func tagFilter(params ListTagsParams) (string, []interface{}) {
+	if params.Prefix == "" {
+		return "", nil
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(params.Prefix)
+	return " WHERE t.name LIKE ?", []interface{}{escaped + "%"}
+}
+
+// ListTags implements TagStore
+func (s *MySQLStore) ListTags(ctx context.Context, params ListTagsParams) ([]models.TagInfo, error) {
+	filter, args := tagFilter(params)
+
+	order, ok := mysqlTagOrders[params.Sort]
+	if !ok {
+		order = mysqlTagOrders[TagSortPopular]
+	}
+
+	query := "SELECT " + tagInfoColumns + " FROM tags t" + filter +
+		fmt.Sprintf(" ORDER BY %s LIMIT ? OFFSET ?", order)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TagInfo{}
+	for rows.Next() {
+		tag, err := scanTagInfo(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// CountTags implements TagStore
+func (s *MySQLStore) CountTags(ctx context.Context, params ListTagsParams) (int, error) {
+	filter, args := tagFilter(params)
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tags t"+filter, args...).Scan(&total)
+	return total, err
+}
+
+// GetTag implements TagStore
+func (s *MySQLStore) GetTag(ctx context.Context, name string) (models.TagInfo, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+tagInfoColumns+" FROM tags t WHERE t.name = ?", name)
+
+	tag, err := scanTagInfo(row)
+	if err == sql.ErrNoRows {
+		return tag, ErrNotFound
+	}
+	return tag, err
+}
+
+// UpdateTagDescription implements TagStore
+func (s *MySQLStore) UpdateTagDescription(ctx context.Context, name, description string) error {
+	var id int64
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM tags WHERE name = ?", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "UPDATE tags SET description = ? WHERE id = ?", description, id)
+	return err
+}
+
+// scanTagInfo reads a row selected with tagInfoColumns
+func scanTagInfo(row rowScanner) (models.TagInfo, error) {
+	var tag models.TagInfo
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.CreatedAt, &tag.QuestionCount)
+	return tag, err
+}
+
+// ResolveTags implements TagStore
+func (s *MySQLStore) ResolveTags(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	placeholders, args := inPlaceholders(names)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ts.alias, t.name
+		FROM tag_synonyms ts
+		JOIN tags t ON t.id = ts.tag_id
+		WHERE ts.alias IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	canonical := make(map[string]string)
+	for rows.Next() {
+		var alias, name string
+		if err := rows.Scan(&alias, &name); err != nil {
+			return nil, err
+		}
+		canonical[strings.ToLower(alias)] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resolveNames(names, func(name string) (string, bool) {
+		resolved, ok := canonical[strings.ToLower(name)]
+		return resolved, ok
+	}), nil
+}
+
+// MergeTags implements TagStore
+func (s *MySQLStore) MergeTags(ctx context.Context, alias, canonical string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var canonicalID int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM tags WHERE name = ? FOR UPDATE", canonical).Scan(&canonicalID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to find canonical tag: %w", err)
+	}
+
+	var aliasID int64
+	var aliasDescription sql.NullString
+	err = tx.QueryRowContext(ctx, "SELECT id, description FROM tags WHERE name = ? FOR UPDATE", alias).
+		Scan(&aliasID, &aliasDescription)
+	switch {
+	case err == sql.ErrNoRows:
+		// The alias is not a tag yet; only the synonym has to be recorded
+	case err != nil:
+		return fmt.Errorf("failed to find alias tag: %w", err)
+	case aliasID == canonicalID:
+		// The names differ only in a way the collation ignores, such as case
+		if _, err := tx.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ?", canonical, canonicalID); err != nil {
+			return fmt.Errorf("failed to rename tag: %w", err)
+		}
+		return tx.Commit()
+	default:
+		statements := []struct {
+			query string
+			args  []interface{}
+		}{
+			{"INSERT IGNORE INTO question_tags (question_id, tag_id) SELECT question_id, ? FROM question_tags WHERE tag_id = ?",
+				[]interface{}{canonicalID, aliasID}},
+			{"DELETE FROM question_tags WHERE tag_id = ?", []interface{}{aliasID}},
+			{"UPDATE tag_synonyms SET tag_id = ? WHERE tag_id = ?", []interface{}{canonicalID, aliasID}},
+			{"UPDATE tags SET description = ? WHERE id = ? AND (description IS NULL OR description = '')",
+				[]interface{}{aliasDescription, canonicalID}},
+			{"DELETE FROM tags WHERE id = ?", []interface{}{aliasID}},
+		}
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+				return fmt.Errorf("failed to merge tags: %w", err)
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO tag_synonyms (alias, tag_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE tag_id = VALUES(tag_id)",
+		alias, canonicalID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record synonym: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RenameTag implements TagStore
+func (s *MySQLStore) RenameTag(ctx context.Context, from, to string) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE tags SET name = ? WHERE name = ?", to, from)
+	if isDuplicateEntry(err) {
+		return ErrConflict
+	} else if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		exists := false
+		if err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM tags WHERE name = ?)", from).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// ListTagSynonyms implements TagStore
+func (s *MySQLStore) ListTagSynonyms(ctx context.Context, tagName string) ([]models.TagSynonym, error) {
+	query := `
+		SELECT ts.alias, ts.tag_id, t.name, ts.created_at
+		FROM tag_synonyms ts
+		JOIN tags t ON t.id = ts.tag_id`
+	var args []interface{}
+	if tagName != "" {
+		query += " WHERE t.name = ?"
+		args = append(args, tagName)
+	}
+	query += " ORDER BY ts.alias"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	synonyms := []models.TagSynonym{}
+	for rows.Next() {
+		var synonym models.TagSynonym
+		if err := rows.Scan(&synonym.Alias, &synonym.TagID, &synonym.TagName, &synonym.CreatedAt); err != nil {
+			return nil, err
+		}
+		synonyms = append(synonyms, synonym)
+	}
+	return synonyms, rows.Err()
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/questions/backend/internal/models"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for unique key violations
+const mysqlDuplicateEntry = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// CreateUser implements UserStore
+func (s *MySQLStore) CreateUser(ctx context.Context, user models.User) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		"INSERT INTO users (username, email, password_hash, role) VALUES (?, ?, ?, ?)",
+		user.Username, user.Email, user.PasswordHash, user.Role,
+	)
+	if isDuplicateEntry(err) {
+		return 0, ErrConflict
+	} else if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetUserByID implements UserStore
+func (s *MySQLStore) GetUserByID(ctx context.Context, id int64) (models.User, error) {
+	return s.getUser(ctx, "id = ?", id)
+}
+
+// GetUserByLogin implements UserStore
+func (s *MySQLStore) GetUserByLogin(ctx context.Context, login string) (models.User, error) {
+	return s.getUser(ctx, "username = ? OR email = ?", login, login)
+}
+
+// SetUserRole implements UserStore
+func (s *MySQLStore) SetUserRole(ctx context.Context, id int64, role string) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ?", role, id)
+	if err != nil {
+		return fmt.Errorf("failed to set user role: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+func (s *MySQLStore) getUser(ctx context.Context, where string, args ...interface{}) (models.User, error) {
+	var user models.User
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id, username, email, password_hash, role, created_at FROM users WHERE "+where+" LIMIT 1", args...,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return user, ErrNotFound
+	}
+	return user, err
+}
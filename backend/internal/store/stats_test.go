@@ -0,0 +1,59 @@
+package store_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+	"github.com/questions/backend/internal/store"
+)
+
+func TestDailyStats(t *testing.T) {
+	ctx := context.Background()
+	for name, st := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			questionID, err := st.CreateQuestion(ctx, models.QuestionCreateRequest{Title: "Tracked question", Content: "Daily stats"}, nil)
+			if err != nil {
+				t.Fatalf("creating question: %v", err)
+			}
+			t.Cleanup(func() { st.DeleteQuestion(ctx, questionID) })
+
+			// Two likes given today, one of them withdrawn again
+			for _, ip := range []string{"192.0.2.1", "192.0.2.2", "192.0.2.1"} {
+				if _, _, err := st.ToggleLike(ctx, questionID, nil, ip); err != nil {
+					t.Fatalf("toggling like: %v", err)
+				}
+			}
+			today := time.Now().UTC()
+			yesterday := today.AddDate(0, 0, -1)
+
+			// Rolling hits up, again, leaves the likes alone
+			for _, views := range []store.DailyViews{{Hits: 4, UniqueVisitors: 2}, {Hits: 5, UniqueVisitors: 3}} {
+				if err := st.SaveViewStats(ctx, today, map[int64]store.DailyViews{questionID: views}); err != nil {
+					t.Fatalf("saving view stats: %v", err)
+				}
+			}
+			if err := st.SaveViewStats(ctx, yesterday, map[int64]store.DailyViews{questionID: {Hits: 1, UniqueVisitors: 1}}); err != nil {
+				t.Fatalf("saving view stats: %v", err)
+			}
+
+			stats, err := st.ListDailyStats(ctx, questionID, yesterday.AddDate(0, 0, -1), today)
+			if err != nil {
+				t.Fatalf("ListDailyStats: %v", err)
+			}
+			want := []models.QuestionDayStats{
+				{Date: yesterday.Format(models.StatsDateLayout), Hits: 1, UniqueVisitors: 1},
+				{Date: today.Format(models.StatsDateLayout), Hits: 5, UniqueVisitors: 3, Likes: 2},
+			}
+			if !reflect.DeepEqual(stats, want) {
+				t.Errorf("daily stats = %+v, want %+v", stats, want)
+			}
+
+			if stats, err := st.ListDailyStats(ctx, questionID, yesterday, yesterday); err != nil || len(stats) != 1 {
+				t.Errorf("stats of yesterday alone = %+v, %v; want one day", stats, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/questions/backend/internal/models"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrConflict is returned when a write would violate a uniqueness constraint
+var ErrConflict = errors.New("record already exists")
+
+// Question answer statuses accepted by ListQuestionsParams.Status
+const (
+	QuestionStatusUnanswered = "unanswered" // no answers yet
+	QuestionStatusAnswered   = "answered"   // at least one answer
+	QuestionStatusAccepted   = "accepted"   // an answer has been accepted
+)
+
+// Search modes accepted by ListQuestionsParams.SearchMode
+const (
+	SearchModeNatural = "natural" // rank by relevance to the free-text query
+	SearchModeBoolean = "boolean" // honor +required, -excluded, "phrases" and prefix* operators
+)
+
+// SortRelevance orders search results by relevance; it is ignored without a search query
+const SortRelevance = "relevance"
+
+// ListQuestionsParams holds the filters, ordering and pagination for listing questions
+type ListQuestionsParams struct {
+	// Tags restricts the list to questions carrying all of the tags, or any
+	// of them when MatchAnyTag is set. Names must not repeat.
+	Tags        []string
+	MatchAnyTag bool
+	// ExcludeTags drops questions carrying any of the tags
+	ExcludeTags []string
+	Search      string
+	// SearchMode is one of the SearchMode constants; empty picks boolean mode
+	// when Search uses boolean operators and natural language mode otherwise
+	SearchMode string
+	// SearchHits, when non-nil, replaces the store's own search: only these
+	// questions match and their scores become the relevance. IndexedStore
+	// sets it from its index.
+	SearchHits map[int64]float64
+	Status     string // one of the QuestionStatus constants, or empty for all
+	Sort       string // created_at, updated_at, like_count, view_count or relevance
+	Order      string // asc or desc
+	Limit      int
+	Offset     int
+	// After, when set, starts the list right after this position in the
+	// list's order (keyset pagination); Offset then counts from there
+	After *QuestionCursor
+}
+
+// QuestionCursor is the position of a question in a sorted list. Only ID and
+// the field of the list's sort are compared.
+type QuestionCursor struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	LikeCount int
+	ViewCount int
+	Relevance float64
+}
+
+// CursorOf returns the list position of a question
+func CursorOf(q models.Question) QuestionCursor {
+	return QuestionCursor{
+		ID:        q.ID,
+		CreatedAt: q.CreatedAt,
+		UpdatedAt: q.UpdatedAt,
+		LikeCount: q.LikeCount,
+		ViewCount: q.ViewCount,
+		Relevance: q.Relevance,
+	}
+}
+
+// sortField resolves the effective sort of the params: relevance only applies
+// to searches and anything unknown falls back to created_at
+func (p ListQuestionsParams) sortField() string {
+	switch p.Sort {
+	case "updated_at", "like_count", "view_count":
+		return p.Sort
+	case SortRelevance:
+		if p.searching() {
+			return SortRelevance
+		}
+	}
+	return "created_at"
+}
+
+// searching reports whether the params restrict the list by a search query
+func (p ListQuestionsParams) searching() bool {
+	return p.Search != "" || p.SearchHits != nil
+}
+
+// searchMode resolves the effective search mode of the params
+func (p ListQuestionsParams) searchMode() string {
+	switch p.SearchMode {
+	case SearchModeNatural, SearchModeBoolean:
+		return p.SearchMode
+	}
+	if strings.ContainsAny(p.Search, `+-"*()<>~`) {
+		return SearchModeBoolean
+	}
+	return SearchModeNatural
+}
+
+// QuestionUpdate describes an edit to a question; nil fields are left unchanged
+type QuestionUpdate struct {
+	Title   *string
+	Content *string
+	// TagNames replaces the question's whole tag set when non-nil
+	TagNames *[]string
+	// EditorID is recorded on the resulting revision; nil for anonymous edits
+	EditorID *int64
+	// RollbackOf is recorded on the resulting revision when the edit restores
+	// an earlier revision
+	RollbackOf *int
+}
+
+// QuestionCounts holds the persisted view and like counts of a question
+type QuestionCounts struct {
+	Views int
+	Likes int
+}
+
+// QuestionStore persists questions and their tags
+type QuestionStore interface {
+	// ListQuestions returns one page of questions matching the params
+	ListQuestions(ctx context.Context, params ListQuestionsParams) ([]models.Question, error)
+	// CountQuestions returns the number of questions matching the params' filters
+	CountQuestions(ctx context.Context, params ListQuestionsParams) (int, error)
+	// GetQuestion returns a question by ID or ErrNotFound
+	GetQuestion(ctx context.Context, id int64) (models.Question, error)
+	// QuestionExists reports whether a question with the given ID exists
+	QuestionExists(ctx context.Context, id int64) (bool, error)
+	// CreateQuestion stores a question with its tags as revision 1 and returns
+	// the new ID. authorID is nil for anonymous questions.
+	CreateQuestion(ctx context.Context, req models.QuestionCreateRequest, authorID *int64) (int64, error)
+	// UpdateQuestion applies an edit, bumps updated_at and records a new
+	// revision, or returns ErrNotFound
+	UpdateQuestion(ctx context.Context, id int64, update QuestionUpdate) error
+	// DeleteQuestion removes a question with its tag links, answers, comments
+	// and likes, or returns ErrNotFound
+	DeleteQuestion(ctx context.Context, id int64) error
+	// SetAcceptedAnswer marks an answer as the question's accepted answer,
+	// or clears it when answerID is nil. It returns ErrNotFound for unknown questions.
+	SetAcceptedAnswer(ctx context.Context, questionID int64, answerID *int64) error
+	// GetQuestionTags returns the tags attached to a question
+	GetQuestionTags(ctx context.Context, questionID int64) ([]models.Tag, error)
+	// GetTagsForQuestions returns the tags of several questions in one query,
+	// keyed by question ID; questions without tags are omitted
+	GetTagsForQuestions(ctx context.Context, questionIDs []int64) (map[int64][]models.Tag, error)
+	// GetCounts returns the persisted view and like counts of a question
+	GetCounts(ctx context.Context, questionID int64) (viewCount, likeCount int, err error)
+	// GetCountsForQuestions returns the persisted counts of several questions
+	// in one query, keyed by question ID; unknown IDs are omitted
+	GetCountsForQuestions(ctx context.Context, questionIDs []int64) (map[int64]QuestionCounts, error)
+	// IncrementViewCount adds one view to the persisted view count
+	IncrementViewCount(ctx context.Context, questionID int64) error
+	// AddViewCounts adds buffered view deltas, keyed by question ID, to the
+	// persisted view counts in one statement. Unknown IDs are ignored.
+	AddViewCounts(ctx context.Context, deltas map[int64]int) error
+}
+
+// Tag orderings accepted by ListTagsParams.Sort
+const (
+	TagSortPopular = "popular" // most questions first
+	TagSortName    = "name"    // alphabetical
+	TagSortNewest  = "newest"  // most recently created first
+)
+
+// ListTagsParams holds the filter, ordering and pagination for listing tags
+type ListTagsParams struct {
+	Prefix string // only tags whose name starts with Prefix
+	Sort   string // one of the TagSort constants
+	Limit  int
+	Offset int
+}
+
+// TagStore reads the tag directory
+type TagStore interface {
+	// ListTags returns one page of tags with their question counts
+	ListTags(ctx context.Context, params ListTagsParams) ([]models.TagInfo, error)
+	// CountTags returns the number of tags matching the params' prefix
+	CountTags(ctx context.Context, params ListTagsParams) (int, error)
+	// GetTag returns a tag by name with its question count, or ErrNotFound
+	GetTag(ctx context.Context, name string) (models.TagInfo, error)
+	// UpdateTagDescription replaces a tag's wiki description, or returns ErrNotFound
+	UpdateTagDescription(ctx context.Context, name, description string) error
+	// ResolveTags maps aliases to their canonical tag names, keeping other
+	// names as they are and dropping duplicates
+	ResolveTags(ctx context.Context, names []string) ([]string, error)
+	// MergeTags makes alias a synonym of the canonical tag in one transaction:
+	// questions tagged with alias are retagged, alias's own synonyms move to
+	// canonical and the alias tag is deleted. It returns ErrNotFound when the
+	// canonical tag does not exist.
+	MergeTags(ctx context.Context, alias, canonical string) error
+	// RenameTag changes a tag's name, returning ErrNotFound for unknown tags
+	// and ErrConflict when the new name is taken
+	RenameTag(ctx context.Context, from, to string) error
+	// ListTagSynonyms returns the synonyms of a tag, or of every tag when
+	// tagName is empty, ordered by alias
+	ListTagSynonyms(ctx context.Context, tagName string) ([]models.TagSynonym, error)
+}
+
+// RevisionStore reads the edit history of questions. Revisions are written by
+// QuestionStore.CreateQuestion and QuestionStore.UpdateQuestion.
+type RevisionStore interface {
+	// ListRevisions returns every revision of a question, oldest first
+	ListRevisions(ctx context.Context, questionID int64) ([]models.QuestionRevision, error)
+	// GetRevision returns one revision of a question or ErrNotFound
+	GetRevision(ctx context.Context, questionID int64, revision int) (models.QuestionRevision, error)
+}
+
+// DailyViews holds a question's hits, every page load, and approximate unique
+// visitors on one day
+type DailyViews struct {
+	Hits           int
+	UniqueVisitors int
+}
+
+// StatsStore persists per-day question analytics
+type StatsStore interface {
+	// SaveViewStats stores the views of questions on one UTC day, replacing
+	// values saved earlier for that day. Unknown questions are skipped.
+	SaveViewStats(ctx context.Context, day time.Time, views map[int64]DailyViews) error
+	// ListDailyStats returns the UTC days from from to to, inclusive, on which
+	// a question had views or likes, oldest first. Likes count the likes given
+	// on each day, including those withdrawn since.
+	ListDailyStats(ctx context.Context, questionID int64, from, to time.Time) ([]models.QuestionDayStats, error)
+}
+
+// CommentStore persists comments on questions and answers
+type CommentStore interface {
+	// ListComments returns the comments on a question itself, newest first
+	ListComments(ctx context.Context, questionID int64) ([]models.Comment, error)
+	// ListAnswerComments returns the comments on every answer of a question,
+	// keyed by answer ID, oldest first
+	ListAnswerComments(ctx context.Context, questionID int64) (map[int64][]models.Comment, error)
+	// AddComment stores a comment and returns the new ID. AuthorID is nil for
+	// anonymous comments and AnswerID is nil for comments on the question itself.
+	AddComment(ctx context.Context, comment models.Comment) (int64, error)
+}
+
+// Answer orderings accepted by AnswerStore.ListAnswers
+const (
+	AnswerSortScore  = "score"  // highest score first, oldest first among ties
+	AnswerSortNewest = "newest" // most recent first
+	AnswerSortOldest = "oldest" // earliest first
+)
+
+// AnswerStore persists answers to questions
+type AnswerStore interface {
+	// ListAnswers returns the answers to a question in the given AnswerSort order
+	ListAnswers(ctx context.Context, questionID int64, sort string) ([]models.Answer, error)
+	// GetAnswer returns an answer by ID or ErrNotFound
+	GetAnswer(ctx context.Context, id int64) (models.Answer, error)
+	// CreateAnswer stores an answer, increments the question's answer count and returns the new ID
+	CreateAnswer(ctx context.Context, answer models.Answer) (int64, error)
+	// UpdateAnswer replaces an answer's content, or returns ErrNotFound
+	UpdateAnswer(ctx context.Context, id int64, content string) error
+}
+
+// LikeStore persists likes on questions
+type LikeStore interface {
+	// ToggleLike adds the liker's like if absent or removes it if present,
+	// keeping questions.like_count in sync. Likes are keyed by userID when it
+	// is non-nil and by clientIP otherwise. It reports whether the question
+	// is liked after the call together with the updated like count.
+	ToggleLike(ctx context.Context, questionID int64, userID *int64, clientIP string) (liked bool, likeCount int, err error)
+}
+
+// UserStore persists user accounts
+type UserStore interface {
+	// CreateUser stores a user and returns the new ID, or ErrConflict if the
+	// username or email is taken
+	CreateUser(ctx context.Context, user models.User) (int64, error)
+	// GetUserByID returns a user or ErrNotFound
+	GetUserByID(ctx context.Context, id int64) (models.User, error)
+	// GetUserByLogin returns the user whose username or email equals login, or ErrNotFound
+	GetUserByLogin(ctx context.Context, login string) (models.User, error)
+	// SetUserRole changes a user's role, or returns ErrNotFound
+	SetUserRole(ctx context.Context, id int64, role string) error
+}
+
+// Store groups every store the API depends on
+type Store interface {
+	QuestionStore
+	RevisionStore
+	TagStore
+	StatsStore
+	CommentStore
+	AnswerStore
+	LikeStore
+	UserStore
+}
+
+// resolveNames maps each name through lookup, keeping unmapped names and
+// dropping duplicates while preserving order
+func resolveNames(names []string, lookup func(name string) (string, bool)) []string {
+	seen := make(map[string]bool, len(names))
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		if canonical, ok := lookup(name); ok {
+			name = canonical
+		}
+		if !seen[name] {
+			seen[name] = true
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
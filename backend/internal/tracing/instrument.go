@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverName names the server in the attributes of request spans
+const serverName = "questions-backend"
+
+// untracedPaths are polled by Prometheus and health probes every few seconds
+var untracedPaths = map[string]bool{"/metrics": true, "/healthz": true, "/readyz": true, "/status": true}
+
+// Middleware starts a span for every request, named after its route and
+// continuing the trace of the caller's traceparent header. The span rides in
+// the request context, so spans started from that context become its
+// children. Prometheus scrapes and health probes are not traced.
+func Middleware() gin.HandlerFunc {
+	return otelgin.Middleware(serverName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
+
+// tracer starts the spans of Redis commands
+var tracer = otel.Tracer("github.com/questions/backend/internal/tracing")
+
+// InstrumentRedis traces the commands rdb runs within a trace, so health
+// probes and Redis recovery checks do not start traces of their own. Spans
+// record command names but never their arguments, which hold keys and client
+// data.
+func InstrumentRedis(rdb *redis.Client) {
+	opts := rdb.Options()
+	rdb.AddHook(redisHook{attrs: []attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.ServerAddress(opts.Addr),
+		semconv.DBNamespace(strconv.Itoa(opts.DB)),
+	}})
+}
+
+// redisHook starts a client span per command or pipeline
+type redisHook struct {
+	attrs []attribute.KeyValue
+}
+
+// DialHook implements redis.Hook
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		name := strings.ToLower(cmd.Name())
+		ctx, span := h.start(ctx, name, semconv.DBOperationName(name))
+		defer span.End()
+
+		err := next(ctx, cmd)
+		h.finish(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = strings.ToLower(cmd.Name())
+		}
+		name := "pipeline"
+		if len(names) > 0 && names[0] == "multi" {
+			name = "multi"
+		}
+		ctx, span := h.start(ctx, name, attribute.StringSlice("db.redis.commands", names))
+		defer span.End()
+
+		err := next(ctx, cmds)
+		h.finish(span, err)
+		return err
+	}
+}
+
+// start starts the span of an operation, or returns the non-recording span of
+// ctx when it is not part of a trace
+func (h redisHook) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, "redis "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(h.attrs...),
+		trace.WithAttributes(attrs...))
+}
+
+// finish records the outcome of an operation; missing keys are not errors
+func (h redisHook) finish(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -28,7 +28,7 @@ go mod tidy
 
 # Build the application
 echo "Building application..."
-go build -o bin/questions_backend ./cmd/main.go
+go build -o bin/questions_backend ./cmd
 
 # Run the application
 echo "Running application..."
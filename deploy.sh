@@ -46,7 +46,7 @@ export CORS_ALLOWED_ORIGINS="http://localhost:3001"
 go run router-patch.go
 
 go mod tidy
-go build -o bin/questions_backend ./cmd/main.go
+go build -o bin/questions_backend ./cmd
 cd ..
 
 # Build frontend
@@ -75,6 +75,19 @@ echo "Starting applications with PM2..."
 pm2 delete all 2>/dev/null || true
 pm2 start ecosystem.config.js
 
+# Wait for the backend to connect to its dependencies
+echo "Waiting for the backend to become ready..."
+for i in $(seq 1 30); do
+  if curl -fs http://localhost:8081/readyz > /dev/null; then
+    echo "Backend is ready"
+    break
+  fi
+  if [ "$i" = 30 ]; then
+    echo "Warning: Backend is not ready after 30s. Check http://localhost:8081/status and pm2 logs questions-backend."
+  fi
+  sleep 1
+done
+
 # Save PM2 configuration to ensure applications restart after server reboot
 echo "Saving PM2 configuration..."
 pm2 save
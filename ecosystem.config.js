@@ -16,7 +16,9 @@ module.exports = {
         REDIS_PORT: '6379',
         SERVER_PORT: '8081',
         JWT_SECRET: 'your-secret-key',
-        CORS_ALLOWED_ORIGINS: 'http://localhost:3001'
+        CORS_ALLOWED_ORIGINS: 'http://localhost:3001',
+        LOG_FORMAT: 'json',
+        LOG_LEVEL: 'info'
       },
       log_date_format: 'YYYY-MM-DD HH:mm:ss',
       time: true,
@@ -27,7 +29,9 @@ module.exports = {
       merge_logs: true,
       autorestart: true,
       max_restarts: 10,
-      restart_delay: 5000
+      restart_delay: 5000,
+      // Longer than SHUTDOWN_TIMEOUT so in-flight requests can drain
+      kill_timeout: 25000
     },
     {
       name: 'questions-frontend',